@@ -0,0 +1,249 @@
+package tfidf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrUnknownDocument is returned when a Corpus operation refers to a document that was not added.
+var ErrUnknownDocument = errors.New("unknown document")
+
+// Corpus is a growing collection of tokenized documents, identified by caller-defined IDs,
+// that keeps the document frequencies of its terms up to date as documents are added,
+// removed or updated, so that the corpus never needs to be re-scanned.
+//
+// Each document is stored as its term counts, computed once when it is added.
+// The vocabulary, the IDF vector and the document vectors are recomputed lazily, the first
+// time they are requested after a change: the stored counts are reweighted with the new IDF
+// and normalized again, without tokens being counted again.
+// A Corpus is not safe for concurrent use.
+//
+// Example:
+//
+//	corpus := NewCorpus()
+//	_ = corpus.Add("a", []string{"this", "is", "a", "sample"})
+//	_ = corpus.Add("b", []string{"another", "example"})
+//	_ = corpus.Remove("a")
+//	vec, _ := corpus.Vector("b")
+type Corpus struct {
+	options *Model // Unfitted model holding the options every snapshot is fitted with
+
+	ids      []string              // Document IDs, in insertion order
+	position map[string]int        // Document ID -> position in ids
+	rows     map[string]termCounts // Document ID -> term counts
+	dfMap    map[string]int        // Term -> number of documents containing it
+	cfMap    map[string]int        // Term -> number of occurrences in the corpus
+
+	model  *Model        // Snapshot fitted on the current documents, nil if out of date
+	matrix *SparseMatrix // TF-IDF matrix of the documents for model, nil if out of date
+}
+
+// NewCorpus creates an empty corpus. The options configure the weighting and the pruning
+// of the vocabulary, like for NewModel.
+func NewCorpus(opts ...ModelOption) *Corpus {
+	return &Corpus{
+		options:  NewModel(opts...),
+		position: make(map[string]int),
+		rows:     make(map[string]termCounts),
+		dfMap:    make(map[string]int),
+		cfMap:    make(map[string]int),
+	}
+}
+
+// Add adds a tokenized document to the corpus.
+// Only the term counts of tokens are kept, so the slice can be reused by the caller.
+//
+// Returns:
+//   - err: Error if a document with the same ID already exists
+func (c *Corpus) Add(id string, tokens []string) error {
+	if _, found := c.rows[id]; found {
+		return fmt.Errorf("document %q already exists", id)
+	}
+	row := newTermCounts(tokens)
+	c.position[id] = len(c.ids)
+	c.ids = append(c.ids, id)
+	c.rows[id] = row
+	c.count(row, 1)
+	c.invalidate()
+	return nil
+}
+
+// Remove removes a document from the corpus.
+//
+// Returns:
+//   - err: ErrUnknownDocument if there is no document with this ID
+func (c *Corpus) Remove(id string) error {
+	row, found := c.rows[id]
+	if !found {
+		return fmt.Errorf("%w: %q", ErrUnknownDocument, id)
+	}
+	i := c.position[id]
+	c.ids = slices.Delete(c.ids, i, i+1)
+	for _, moved := range c.ids[i:] {
+		c.position[moved]--
+	}
+	delete(c.position, id)
+	delete(c.rows, id)
+	c.count(row, -1)
+	c.invalidate()
+	return nil
+}
+
+// Update replaces the tokens of a document, keeping its position in the corpus.
+//
+// Returns:
+//   - err: ErrUnknownDocument if there is no document with this ID
+func (c *Corpus) Update(id string, tokens []string) error {
+	old, found := c.rows[id]
+	if !found {
+		return fmt.Errorf("%w: %q", ErrUnknownDocument, id)
+	}
+	row := newTermCounts(tokens)
+	c.count(old, -1)
+	c.rows[id] = row
+	c.count(row, 1)
+	c.invalidate()
+	return nil
+}
+
+// Len returns the number of documents in the corpus.
+func (c *Corpus) Len() int {
+	return len(c.ids)
+}
+
+// Documents returns the IDs of the documents in the corpus, in insertion order,
+// which is also the order of the rows of Matrix. The returned slice must not be modified.
+func (c *Corpus) Documents() []string {
+	return c.ids
+}
+
+// Model returns a model fitted on the current documents, to vectorize queries against the corpus
+// vocabulary or to persist it. It is recomputed only if the corpus changed since the last call,
+// and is not affected by later changes.
+//
+// Returns:
+//   - model: Fitted model, as if Fit had been called on the documents in insertion order
+//   - err: Error if the corpus is empty or if fitting fails, see Model.Fit
+func (c *Corpus) Model() (*Model, error) {
+	if c.model != nil {
+		return c.model, nil
+	}
+	if len(c.ids) == 0 {
+		return nil, errors.New("empty corpus")
+	}
+	var cfMap map[string]int
+	if c.options.maxFeatures > 0 {
+		cfMap = c.cfMap
+	}
+	model := *c.options
+	if err := model.fitFrequencies(context.Background(), c.dfMap, cfMap, len(c.ids), func(index map[string]int) (*SparseMatrix, error) {
+		return c.counts(index), nil
+	}); err != nil {
+		return nil, err
+	}
+	c.model = &model
+	return c.model, nil
+}
+
+// Matrix returns the TF-IDF matrix of the documents in CSR format, with rows in the order
+// of Documents and columns in the order of the model vocabulary.
+// The stored term counts are reweighted only if the corpus changed since the last call.
+// The matrix must not be modified.
+func (c *Corpus) Matrix() (*SparseMatrix, error) {
+	if c.matrix != nil {
+		return c.matrix, nil
+	}
+	model, err := c.Model()
+	if err != nil {
+		return nil, err
+	}
+	if c.matrix, err = model.fitted.TfIdfSparse(c.counts(model.index), model.idf); err != nil {
+		return nil, err
+	}
+	return c.matrix, nil
+}
+
+// Vector returns the TF-IDF vector of a document, against the current vocabulary.
+//
+// Returns:
+//   - vec: Sparse TF-IDF vector, sharing memory with Matrix
+//   - err: ErrUnknownDocument if there is no document with this ID, or an error from Matrix
+func (c *Corpus) Vector(id string) (SparseVector, error) {
+	i, found := c.position[id]
+	if !found {
+		return SparseVector{}, fmt.Errorf("%w: %q", ErrUnknownDocument, id)
+	}
+	matrix, err := c.Matrix()
+	if err != nil {
+		return SparseVector{}, err
+	}
+	return matrix.Row(i), nil
+}
+
+// count adds delta to the document and corpus frequencies of the terms of a document,
+// dropping terms that no longer occur in any document.
+func (c *Corpus) count(row termCounts, delta int) {
+	for k, term := range row.terms {
+		c.dfMap[term] += delta
+		c.cfMap[term] += delta * row.counts[k]
+		if c.dfMap[term] == 0 {
+			delete(c.dfMap, term)
+			delete(c.cfMap, term)
+		}
+	}
+}
+
+// invalidate marks the fitted model and the matrix as out of date.
+func (c *Corpus) invalidate() {
+	c.model = nil
+	c.matrix = nil
+}
+
+// counts returns the term count matrix of the documents, in insertion order, against a vocabulary index.
+// Terms missing from the index are ignored, but still count in the statistics of their document.
+func (c *Corpus) counts(index map[string]int) *SparseMatrix {
+	s := NewSparseMatrix(len(index))
+	for _, id := range c.ids {
+		// Terms are sorted like the vocabulary, so their columns are increasing
+		row := c.rows[id]
+		var stats docStats
+		for k, term := range row.terms {
+			stats.add(float64(row.counts[k]))
+			if j, found := index[term]; found {
+				s.Indices = append(s.Indices, j)
+				s.Values = append(s.Values, float64(row.counts[k]))
+			}
+		}
+		s.Indptr = append(s.Indptr, len(s.Indices))
+		s.stats = append(s.stats, stats)
+	}
+	return s
+}
+
+// termCounts holds the distinct terms of a document, sorted alphabetically, and their number of occurrences.
+type termCounts struct {
+	terms  []string
+	counts []int
+}
+
+// newTermCounts counts the terms of a tokenized document.
+func newTermCounts(tokens []string) termCounts {
+	counts := make(map[string]int, len(tokens))
+	for _, term := range tokens {
+		counts[term]++
+	}
+	row := termCounts{
+		terms:  make([]string, 0, len(counts)),
+		counts: make([]int, 0, len(counts)),
+	}
+	for term := range counts {
+		row.terms = append(row.terms, term)
+	}
+	slices.Sort(row.terms)
+	for _, term := range row.terms {
+		row.counts = append(row.counts, counts[term])
+	}
+	return row
+}
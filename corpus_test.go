@@ -0,0 +1,183 @@
+package tfidf
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCorpus(t *testing.T) {
+	docs := map[string][]string{
+		"a": {"this", "is", "a", "sample", "document"},
+		"b": {"this", "document", "is", "another", "example"},
+		"c": {"and", "this", "is", "a", "different", "one"},
+		"d": {"example", "example", "one"},
+		"e": {"rare", "rare", "example"},
+	}
+
+	tests := []struct {
+		name    string
+		opts    []ModelOption
+		apply   func(c *Corpus) error
+		wantIDs []string
+		want    []string // Keys of docs holding the current tokens of wantIDs
+	}{
+		{
+			name: "add",
+			apply: func(c *Corpus) error {
+				return errors.Join(c.Add("a", docs["a"]), c.Add("b", docs["b"]), c.Add("c", docs["c"]))
+			},
+			wantIDs: []string{"a", "b", "c"},
+			want:    []string{"a", "b", "c"},
+		},
+		{
+			name: "remove",
+			apply: func(c *Corpus) error {
+				return errors.Join(c.Add("a", docs["a"]), c.Add("b", docs["b"]), c.Add("c", docs["c"]), c.Remove("b"))
+			},
+			wantIDs: []string{"a", "c"},
+			want:    []string{"a", "c"},
+		},
+		{
+			name: "update",
+			apply: func(c *Corpus) error {
+				return errors.Join(c.Add("a", docs["a"]), c.Add("b", docs["b"]), c.Update("a", docs["d"]))
+			},
+			wantIDs: []string{"a", "b"},
+			want:    []string{"d", "b"},
+		},
+		{
+			name: "remove all and add",
+			apply: func(c *Corpus) error {
+				return errors.Join(c.Add("a", docs["a"]), c.Remove("a"), c.Add("d", docs["d"]))
+			},
+			wantIDs: []string{"d"},
+			want:    []string{"d"},
+		},
+		{
+			name: "reused token buffer",
+			apply: func(c *Corpus) error {
+				buf := append([]string(nil), docs["a"]...)
+				err := c.Add("a", buf)
+				buf = append(buf[:0], docs["b"]...)
+				return errors.Join(err, c.Add("b", buf), c.Remove("a"))
+			},
+			wantIDs: []string{"b"},
+			want:    []string{"b"},
+		},
+		{
+			name: "pivoted",
+			opts: []ModelOption{WithVectorizer(NewTfIdfVectorizer(WithNormLevel(PivotedUniqueNorm))), WithIdfScheme(PlainIdf)},
+			apply: func(c *Corpus) error {
+				return errors.Join(c.Add("a", docs["a"]), c.Add("b", docs["b"]), c.Add("e", docs["e"]), c.Update("b", docs["d"]))
+			},
+			wantIDs: []string{"a", "b", "e"},
+			want:    []string{"a", "d", "e"},
+		},
+		{
+			name: "pruning",
+			opts: []ModelOption{WithMinDf(2), WithMaxFeatures(3)},
+			apply: func(c *Corpus) error {
+				return errors.Join(c.Add("a", docs["a"]), c.Add("b", docs["b"]), c.Add("c", docs["c"]), c.Add("d", docs["d"]), c.Add("e", docs["e"]), c.Remove("c"), c.Remove("e"))
+			},
+			wantIDs: []string{"a", "b", "d"},
+			want:    []string{"a", "b", "d"},
+		},
+		{
+			name: "pruning with length TF",
+			opts: []ModelOption{WithVectorizer(NewTfIdfVectorizer(WithTfScheme(LengthTf), WithNormLevel(NoNorm))), WithMinDf(2)},
+			apply: func(c *Corpus) error {
+				return errors.Join(c.Add("a", docs["a"]), c.Add("d", docs["d"]), c.Add("e", docs["e"]))
+			},
+			wantIDs: []string{"a", "d", "e"},
+			want:    []string{"a", "d", "e"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			corpus := NewCorpus(tt.opts...)
+			if err := tt.apply(corpus); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(corpus.Documents(), tt.wantIDs) {
+				t.Fatalf("Documents() = %v, want %v", corpus.Documents(), tt.wantIDs)
+			}
+
+			// The corpus must match a model fitted from scratch on its current documents
+			var tokens [][]string
+			for _, key := range tt.want {
+				tokens = append(tokens, docs[key])
+			}
+			model := NewModel(tt.opts...)
+			want, err := model.FitTransform(tokens)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fitted, err := corpus.Model()
+			if err != nil {
+				t.Fatalf("Model() error = %v", err)
+			}
+			if !slices.Equal(fitted.Vocabulary(), model.Vocabulary()) {
+				t.Errorf("Vocabulary() = %v, want %v", fitted.Vocabulary(), model.Vocabulary())
+			}
+			if !slices.Equal(fitted.DocumentFrequencies(), model.DocumentFrequencies()) {
+				t.Errorf("DocumentFrequencies() = %v, want %v", fitted.DocumentFrequencies(), model.DocumentFrequencies())
+			}
+			for i, id := range tt.wantIDs {
+				vec, err := corpus.Vector(id)
+				if err != nil {
+					t.Fatalf("Vector(%q) error = %v", id, err)
+				}
+				if got := vec.Dense(len(model.Vocabulary())); !almostEqualSlices(got, want[i], 1e-12) {
+					t.Errorf("Vector(%q) = %v, want %v", id, got, want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCorpus_Lazy(t *testing.T) {
+	corpus := NewCorpus()
+	_ = corpus.Add("a", []string{"apple", "banana"})
+	_ = corpus.Add("b", []string{"banana"})
+
+	first, _ := corpus.Model()
+	if second, _ := corpus.Model(); second != first {
+		t.Error("Model() was recomputed without changes")
+	}
+	firstIdf := slices.Clone(first.Idf())
+
+	_ = corpus.Add("c", []string{"cherry"})
+	third, _ := corpus.Model()
+	if third == first {
+		t.Fatal("Model() was not recomputed after Add")
+	}
+	if !slices.Equal(first.Idf(), firstIdf) {
+		t.Error("previous snapshot was modified by Add")
+	}
+	if got, want := third.Vocabulary(), []string{"apple", "banana", "cherry"}; !slices.Equal(got, want) {
+		t.Errorf("Vocabulary() = %v, want %v", got, want)
+	}
+}
+
+func TestCorpus_Errors(t *testing.T) {
+	corpus := NewCorpus()
+	if _, err := corpus.Model(); err == nil {
+		t.Error("Model() on empty corpus: expected error")
+	}
+	if err := corpus.Remove("a"); !errors.Is(err, ErrUnknownDocument) {
+		t.Errorf("Remove() error = %v, want ErrUnknownDocument", err)
+	}
+	if err := corpus.Update("a", []string{"x"}); !errors.Is(err, ErrUnknownDocument) {
+		t.Errorf("Update() error = %v, want ErrUnknownDocument", err)
+	}
+	if _, err := corpus.Vector("a"); !errors.Is(err, ErrUnknownDocument) {
+		t.Errorf("Vector() error = %v, want ErrUnknownDocument", err)
+	}
+	_ = corpus.Add("a", []string{"x"})
+	if err := corpus.Add("a", []string{"y"}); err == nil {
+		t.Error("Add() with duplicate ID: expected error")
+	}
+}
@@ -0,0 +1,191 @@
+package tfidf
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"slices"
+
+	"github.com/rioloc/tfidf-go/internal/parallel"
+)
+
+// HashingVectorizer maps tokens to a fixed number of feature columns with a hash function,
+// instead of a vocabulary learned from the corpus, like scikit-learn's HashingVectorizer.
+// It is stateless: no vocabulary is stored, so it needs no fitting, uses constant memory,
+// and vectorizes documents from any number of processes consistently.
+//
+// Tokens are hashed with the 32-bit MurmurHash3 used by scikit-learn, so the columns match
+// those of scikit-learn for the same tokens and number of features. Distinct tokens colliding
+// in the same column add up; with alternate signs, half of the tokens count negatively, so that
+// collisions tend to cancel out instead of inflating the column.
+// Columns cannot be mapped back to tokens.
+//
+// Example:
+//
+//	hv := NewHashingVectorizer(WithNumFeatures(1 << 18), WithAlternateSign(false))
+//	counts, _ := hv.Transform(tokens)
+//	tfIdfMat, _ := NewIdfTransformer(nil, SmoothIdf).FitTransform(counts)
+type HashingVectorizer struct {
+	numFeatures   int  // Number of feature columns tokens are hashed into
+	alternateSign bool // Whether the sign of each token count is derived from its hash
+	workers       int  // Number of goroutines hashing documents, 0 for GOMAXPROCS
+}
+
+// HashingOption is a functional option for configuring HashingVectorizer.
+type HashingOption func(*HashingVectorizer)
+
+// NewHashingVectorizer creates a new hashing vectorizer with the specified options.
+// By default, it uses 2^20 features and alternate signs, like scikit-learn does.
+func NewHashingVectorizer(opts ...HashingOption) *HashingVectorizer {
+	h := &HashingVectorizer{
+		numFeatures:   1 << 20,
+		alternateSign: true,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// WithNumFeatures sets the number of feature columns. Fewer features use less memory,
+// at the cost of more collisions between tokens. A power of two is recommended.
+func WithNumFeatures(n int) HashingOption {
+	return func(h *HashingVectorizer) {
+		h.numFeatures = n
+	}
+}
+
+// WithAlternateSign sets whether token counts are signed according to their hash.
+// Disable it to get non-negative counts, which an IdfTransformer requires with TF schemes
+// other than RawTf and BinaryTf, e.g. taking the log of the counts, and with pivoted normalizations.
+func WithAlternateSign(alternate bool) HashingOption {
+	return func(h *HashingVectorizer) {
+		h.alternateSign = alternate
+	}
+}
+
+// WithHashingWorkers sets the number of goroutines Transform shards documents across,
+// 1 to run sequentially. A number lower than 1 means GOMAXPROCS.
+func WithHashingWorkers(n int) HashingOption {
+	return func(h *HashingVectorizer) {
+		h.workers = n
+	}
+}
+
+// NumFeatures returns the number of feature columns.
+func (h *HashingVectorizer) NumFeatures() int {
+	return h.numFeatures
+}
+
+// Feature returns the column a token is hashed into, and the value each of its occurrences adds:
+// 1, or -1 if alternate signs are enabled and the hash is negative.
+func (h *HashingVectorizer) Feature(token string) (column int, sign float64) {
+	hash := int32(murmur3(token, 0))
+	if hash == math.MinInt32 {
+		// abs(MinInt32) overflows; this is how scikit-learn maps it
+		column = (math.MaxInt32 - (h.numFeatures - 1)) % h.numFeatures
+	} else {
+		column = int(max(hash, -hash)) % h.numFeatures
+	}
+	sign = 1
+	if h.alternateSign && hash < 0 {
+		sign = -1
+	}
+	return column, sign
+}
+
+// Transform hashes the tokens of each document into a term count matrix in CSR format,
+// with NumFeatures columns. Columns whose counts cancel out are not stored.
+// Documents are sharded across the workers set with WithHashingWorkers, GOMAXPROCS by default.
+//
+// Parameters:
+//   - tokens: Tokenized documents where tokens[i] contains all tokens for document i
+//
+// Returns:
+//   - counts: Sparse term count matrix [documents][features], to weight with an IdfTransformer
+//   - err: Error if the number of features is not positive
+func (h *HashingVectorizer) Transform(tokens [][]string) (*SparseMatrix, error) {
+	if h.numFeatures <= 0 {
+		return nil, errors.New("invalid number of features")
+	}
+
+	shards := make([]*SparseMatrix, parallel.Workers(h.workers, len(tokens)))
+	_ = parallel.For(h.workers, len(tokens), func(shard, lo, hi int) error {
+		s := NewSparseMatrix(h.numFeatures)
+		for _, doc := range tokens[lo:hi] {
+			// Count tokens by column, then emit the non-zero columns in increasing order
+			counts := make(map[int]float64)
+			for _, token := range doc {
+				column, sign := h.Feature(token)
+				counts[column] += sign
+			}
+			start := len(s.Indices)
+			for column, count := range counts {
+				if count != 0 {
+					s.Indices = append(s.Indices, column)
+				}
+			}
+			slices.Sort(s.Indices[start:])
+			for _, column := range s.Indices[start:] {
+				s.Values = append(s.Values, counts[column])
+			}
+			s.Indptr = append(s.Indptr, len(s.Indices))
+		}
+		shards[shard] = s
+		return nil
+	})
+
+	s := shards[0]
+	for _, shard := range shards[1:] {
+		s.appendRows(shard)
+	}
+	return s, nil
+}
+
+// murmur3 returns the 32-bit MurmurHash3 (x86 variant) of the bytes of s.
+func murmur3(s string, seed uint32) uint32 {
+	const (
+		c1 = 0xcc9e2d51
+		c2 = 0x1b873593
+	)
+	h := seed
+	n := len(s)
+
+	// Body: 4-byte little-endian blocks
+	i := 0
+	for ; i+4 <= n; i += 4 {
+		k := uint32(s[i]) | uint32(s[i+1])<<8 | uint32(s[i+2])<<16 | uint32(s[i+3])<<24
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+
+	// Tail: the remaining 1 to 3 bytes
+	var k uint32
+	switch n - i {
+	case 3:
+		k ^= uint32(s[i+2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(s[i+1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(s[i])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+
+	// Finalization: force all bits to avalanche
+	h ^= uint32(n)
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
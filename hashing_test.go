@@ -0,0 +1,106 @@
+package tfidf
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestMurmur3(t *testing.T) {
+	tests := []struct {
+		input string
+		seed  uint32
+		want  uint32
+	}{
+		{input: "", seed: 0, want: 0},
+		{input: "", seed: 1, want: 0x514e28b7},
+		{input: "abc", seed: 0, want: 0xb3dd93fa},
+		{input: "aaaa", seed: 0x9747b28c, want: 0x5a97808a},
+		{input: "hello", seed: 0, want: 0x248bfa47},
+		{input: "The quick brown fox jumps over the lazy dog", seed: 0, want: 0x2e4ff723},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := murmur3(tt.input, tt.seed); got != tt.want {
+				t.Errorf("murmur3(%q, %#x) = %#x, want %#x", tt.input, tt.seed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashingVectorizer_Feature(t *testing.T) {
+	// murmur3("hello") = 613153351, murmur3("abc") = -1277324294 as signed 32-bit integers
+	tests := []struct {
+		name       string
+		hv         *HashingVectorizer
+		token      string
+		wantColumn int
+		wantSign   float64
+	}{
+		{name: "positive hash", hv: NewHashingVectorizer(), token: "hello", wantColumn: 613153351 % (1 << 20), wantSign: 1},
+		{name: "negative hash", hv: NewHashingVectorizer(), token: "abc", wantColumn: 1277324294 % (1 << 20), wantSign: -1},
+		{name: "no alternate sign", hv: NewHashingVectorizer(WithAlternateSign(false), WithNumFeatures(10)), token: "abc", wantColumn: 4, wantSign: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			column, sign := tt.hv.Feature(tt.token)
+			if column != tt.wantColumn || sign != tt.wantSign {
+				t.Errorf("Feature(%q) = (%d, %v), want (%d, %v)", tt.token, column, sign, tt.wantColumn, tt.wantSign)
+			}
+		})
+	}
+}
+
+func TestHashingVectorizer_Transform(t *testing.T) {
+	hv := NewHashingVectorizer(WithNumFeatures(4))
+
+	// Find two tokens hashed into the same column with opposite signs, which cancel out
+	var pos, neg string
+	for k := 0; pos == "" || neg == ""; k++ {
+		token := fmt.Sprintf("t%d", k)
+		column, sign := hv.Feature(token)
+		switch {
+		case column == 0 && sign > 0 && pos == "":
+			pos = token
+		case column == 0 && sign < 0 && neg == "":
+			neg = token
+		}
+	}
+
+	tokens := [][]string{
+		{"hello", "abc", "hello"},
+		{pos, neg},
+		{},
+		{pos, pos, neg},
+	}
+	got, err := hv.Transform(tokens)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if got.Cols != 4 || got.Rows() != len(tokens) {
+		t.Fatalf("Transform() shape = %dx%d, want %dx4", got.Rows(), got.Cols, len(tokens))
+	}
+
+	want := make([][]float64, len(tokens))
+	for i, doc := range tokens {
+		want[i] = make([]float64, 4)
+		for _, token := range doc {
+			column, sign := hv.Feature(token)
+			want[i][column] += sign
+		}
+	}
+	for i := range want {
+		if !slices.Equal(got.Row(i).Dense(4), want[i]) {
+			t.Errorf("row %d = %v, want %v", i, got.Row(i).Dense(4), want[i])
+		}
+	}
+	if n := len(got.Row(1).Values); n != 0 {
+		t.Errorf("row 1 stores %d values, want 0 for cancelled counts", n)
+	}
+
+	if _, err := NewHashingVectorizer(WithNumFeatures(0)).Transform(tokens); err == nil {
+		t.Error("Transform() with 0 features: expected error")
+	}
+}
@@ -0,0 +1,108 @@
+package tfidf
+
+import (
+	"errors"
+	"math"
+	"slices"
+)
+
+// IdfScheme represents the formula used to turn document frequencies into IDF weights.
+// In the formulas below, N is the number of documents and df the number of documents containing the term.
+type IdfScheme int
+
+const (
+	// SmoothIdf uses log((N + 1) / (df + 1)) + 1, as if an extra document contained every term once.
+	// Equivalent to scikit-learn smooth_idf=True (default).
+	SmoothIdf IdfScheme = iota
+
+	// StandardIdf uses log(N / df) + 1. Equivalent to scikit-learn smooth_idf=False.
+	StandardIdf
+
+	// PlainIdf uses log(N / df), the textbook formula: a term found in every document weighs 0.
+	// The "t" letter of the SMART notation.
+	PlainIdf
+
+	// ProbabilisticIdf uses max(0, log((N - df) / df)): terms found in at least half
+	// of the documents weigh 0. The "p" letter of the SMART notation.
+	ProbabilisticIdf
+
+	// BM25Idf uses log((N - df + 0.5) / (df + 0.5) + 1), the always positive variant
+	// used by Lucene's BM25 similarity.
+	BM25Idf
+
+	// MaxIdf uses log(max df / (1 + df)), where max df is the highest document frequency
+	// in the vocabulary, so weights are relative to the most common term.
+	// The terms with the highest document frequency weigh log(max df / (1 + max df)),
+	// which is negative: they keep a negative weight after normalization, as MaxNorm and
+	// L1Norm divide by absolute values. It has no scikit-learn equivalent, so ExportSklearn
+	// rejects it, and ImportSklearn rejects negative IDF values.
+	MaxIdf
+
+	// NoIdf weighs every term 1, leaving term frequencies unchanged.
+	// The "n" letter of the document frequency position of the SMART notation.
+	NoIdf
+)
+
+// IdfFromDf converts a document frequency vector into an IDF vector using the given scheme.
+// Terms with a zero document frequency, which only occur with a vocabulary not learned from
+// the corpus, are weighted as if they appeared in a single document, except with SmoothIdf,
+// BM25Idf, MaxIdf and NoIdf which handle them natively.
+//
+// Parameters:
+//   - dfVec: Document frequency vector [terms] from Df()
+//   - total: Number of documents the frequencies were counted on
+//   - scheme: The IDF formula (SmoothIdf, StandardIdf, PlainIdf, ProbabilisticIdf, BM25Idf, MaxIdf or NoIdf)
+//
+// Returns:
+//   - idfVec: IDF vector [terms] where element [j] is the IDF score for the term of dfVec[j]
+//   - err: Error if the scheme is invalid
+//
+// Example:
+//
+//	dfVec := Df(vocabulary, tokens)
+//	idfVec, _ := IdfFromDf(dfVec, len(tokens), PlainIdf) // log(N / df)
+func IdfFromDf(dfVec []int, total int, scheme IdfScheme) (idfVec []float64, err error) {
+	n := float64(total)
+	var maxDf float64
+	if scheme == MaxIdf && len(dfVec) > 0 {
+		maxDf = float64(slices.Max(dfVec))
+	}
+
+	idfVec = make([]float64, len(dfVec))
+	for j, docCount := range dfVec {
+		df := float64(docCount)
+		switch scheme {
+		case SmoothIdf:
+			// Add-one smoothing: prevents log(0) and reduces impact of very rare terms
+			idfVec[j] = math.Log((n+1)/(df+1)) + 1
+		case StandardIdf:
+			idfVec[j] = math.Log(n/max(df, 1)) + 1
+		case PlainIdf:
+			idfVec[j] = math.Log(n / max(df, 1))
+		case ProbabilisticIdf:
+			df = max(df, 1)
+			idfVec[j] = max(0, math.Log((n-df)/df))
+		case BM25Idf:
+			idfVec[j] = math.Log((n-df+0.5)/(df+0.5) + 1)
+		case MaxIdf:
+			idfVec[j] = math.Log(maxDf / (1 + df))
+		case NoIdf:
+			idfVec[j] = 1
+		default:
+			return nil, errors.New("invalid IDF scheme")
+		}
+	}
+	return idfVec, nil
+}
+
+// WithIdfScheme sets the formula used by the model to compute the IDF vector.
+// It replaces any previous WithSmoothing option.
+//
+// Example:
+//
+//	model := NewModel(WithIdfScheme(PlainIdf))
+func WithIdfScheme(scheme IdfScheme) ModelOption {
+	return func(m *Model) {
+		m.idfScheme = scheme
+	}
+}
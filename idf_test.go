@@ -0,0 +1,74 @@
+package tfidf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIdfFromDf(t *testing.T) {
+	// N = 4 documents, max df = 4
+	dfVec := []int{1, 2, 4, 0}
+	ln := math.Log
+
+	tests := []struct {
+		name   string
+		scheme IdfScheme
+		want   []float64
+	}{
+		{name: "Smooth", scheme: SmoothIdf, want: []float64{ln(5.0/2) + 1, ln(5.0/3) + 1, 1, ln(5) + 1}},
+		{name: "Standard", scheme: StandardIdf, want: []float64{ln(4) + 1, ln(2) + 1, 1, ln(4) + 1}},
+		{name: "Plain", scheme: PlainIdf, want: []float64{ln(4), ln(2), 0, ln(4)}},
+		{name: "Probabilistic", scheme: ProbabilisticIdf, want: []float64{ln(3), 0, 0, ln(3)}},
+		{name: "BM25", scheme: BM25Idf, want: []float64{ln(3.5/1.5 + 1), ln(2.5/2.5 + 1), ln(0.5/4.5 + 1), ln(4.5/0.5 + 1)}},
+		{name: "Max", scheme: MaxIdf, want: []float64{ln(2), ln(4.0 / 3), ln(4.0 / 5), ln(4)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IdfFromDf(dfVec, 4, tt.scheme)
+			if err != nil {
+				t.Fatalf("IdfFromDf error: %v", err)
+			}
+			if !almostEqualSlices(got, tt.want, tol) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := IdfFromDf(dfVec, 4, IdfScheme(99)); err == nil {
+		t.Errorf("IdfFromDf() expected error for invalid scheme")
+	}
+	if got, err := IdfFromDf(nil, 4, MaxIdf); err != nil || len(got) != 0 {
+		t.Errorf("IdfFromDf(nil): got %v, %v", got, err)
+	}
+}
+
+func TestModel_IdfScheme(t *testing.T) {
+	tokens := [][]string{
+		{"the", "cat", "sat"},
+		{"the", "dog", "sat"},
+		{"the", "cat"},
+	}
+
+	m := NewModel(WithIdfScheme(PlainIdf))
+	if err := m.Fit(tokens); err != nil {
+		t.Fatalf("Fit error: %v", err)
+	}
+	want, _ := IdfFromDf(m.DocumentFrequencies(), 3, PlainIdf)
+	if !almostEqualSlices(m.Idf(), want, tol) {
+		t.Errorf("idf: got %v, want %v", m.Idf(), want)
+	}
+
+	// WithSmoothing(false) selects the sklearn smooth_idf=False formula
+	m = NewModel(WithIdfScheme(PlainIdf), WithSmoothing(false))
+	if err := m.Fit(tokens); err != nil {
+		t.Fatalf("Fit error: %v", err)
+	}
+	if !almostEqualSlices(m.Idf(), Idf(m.Vocabulary(), tokens, false), tol) {
+		t.Errorf("idf: got %v, want %v", m.Idf(), Idf(m.Vocabulary(), tokens, false))
+	}
+
+	if err := NewModel(WithIdfScheme(IdfScheme(99))).Fit(tokens); err == nil {
+		t.Errorf("Fit() expected error for invalid IDF scheme")
+	}
+}
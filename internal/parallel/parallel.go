@@ -0,0 +1,43 @@
+// Package parallel shards loops over documents across goroutines.
+package parallel
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Workers returns the number of goroutines to process n items with: workers, or GOMAXPROCS
+// if workers is not positive, and never more than n.
+func Workers(workers, n int) int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return max(1, min(workers, n))
+}
+
+// For splits [0, n) into contiguous shards, one per worker, and calls fn on each shard concurrently,
+// with the shard number in [0, Workers(workers, n)). With a single worker, fn runs on the calling goroutine.
+// It returns once all calls have returned, with the error of the first failed shard.
+func For(workers, n int, fn func(shard, lo, hi int) error) error {
+	workers = Workers(workers, n)
+	if workers == 1 {
+		return fn(0, 0, n)
+	}
+
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for shard := 0; shard < workers; shard++ {
+		wg.Add(1)
+		go func(shard int) {
+			defer wg.Done()
+			errs[shard] = fn(shard, shard*n/workers, (shard+1)*n/workers)
+		}(shard)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
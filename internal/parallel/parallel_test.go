@@ -0,0 +1,71 @@
+package parallel
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestWorkers(t *testing.T) {
+	tests := []struct {
+		name    string
+		workers int
+		n       int
+		want    int
+	}{
+		{name: "explicit", workers: 3, n: 10, want: 3},
+		{name: "capped by items", workers: 8, n: 2, want: 2},
+		{name: "no items", workers: 4, n: 0, want: 1},
+		{name: "default", workers: 0, n: 1 << 20, want: runtime.GOMAXPROCS(0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Workers(tt.workers, tt.n); got != tt.want {
+				t.Errorf("Workers(%d, %d) = %d, want %d", tt.workers, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFor(t *testing.T) {
+	for _, workers := range []int{1, 3, 7, 20} {
+		n := 10
+		visits := make([]int, n)
+		shards := make([]bool, Workers(workers, n))
+		err := For(workers, n, func(shard, lo, hi int) error {
+			shards[shard] = true
+			for i := lo; i < hi; i++ {
+				visits[i]++
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("For(%d) error = %v", workers, err)
+		}
+		for i, v := range visits {
+			if v != 1 {
+				t.Errorf("For(%d) visited item %d %d times", workers, i, v)
+			}
+		}
+		for shard, ran := range shards {
+			if !ran {
+				t.Errorf("For(%d) did not run shard %d", workers, shard)
+			}
+		}
+	}
+
+	errFirst, errLast := errors.New("first"), errors.New("last")
+	err := For(4, 8, func(shard, lo, hi int) error {
+		switch shard {
+		case 1:
+			return errFirst
+		case 3:
+			return errLast
+		}
+		return nil
+	})
+	if err != errFirst {
+		t.Errorf("For() error = %v, want %v", err, errFirst)
+	}
+}
@@ -0,0 +1,392 @@
+package tfidf
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rioloc/tfidf-go/internal/parallel"
+)
+
+// ErrNotFitted is returned when a Model is used before Fit has been called.
+var ErrNotFitted = errors.New("model is not fitted")
+
+// Model is a stateful TF-IDF model that learns a vocabulary and the IDF vector
+// from a corpus, and then vectorizes new documents against that frozen vocabulary.
+//
+// It mirrors the fit/transform workflow of scikit-learn's TfidfVectorizer:
+// callers no longer need to carry the vocabulary and the IDF vector around by hand.
+//
+// Example:
+//
+//	model := NewModel()
+//	docsMat, _ := model.FitTransform(tokens)   // learn vocabulary and IDF, vectorize corpus
+//	queryMat, _ := model.Transform(queryTokens) // vectorize new documents with the same vocabulary
+type Model struct {
+	vectorizer *TfIdfVectorizer // Weighting and normalization applied on Transform
+	idfScheme  IdfScheme        // Formula used to compute the IDF
+
+	queryVectorizer *TfIdfVectorizer // Optional weighting and normalization applied on TransformQuery
+	queryIdfScheme  IdfScheme        // Formula used to compute the query IDF, with queryVectorizer
+
+	minDf       dfBound // Terms in fewer documents are pruned on Fit
+	maxDf       dfBound // Terms in more documents are pruned on Fit
+	maxFeatures int     // Maximum vocabulary size, 0 for no limit
+
+	vocabulary []string       // Ordered list of unique terms learned during Fit
+	pruned     []string       // Terms removed from the vocabulary during Fit
+	index      map[string]int // Term -> position in vocabulary
+	df         []int          // Document frequency for each vocabulary term
+	idf        []float64      // IDF score for each vocabulary term
+	nDocs      int            // Number of documents seen during Fit
+
+	fitted      *TfIdfVectorizer // vectorizer with the corpus pivot learned during Fit, if any
+	fittedQuery *TfIdfVectorizer // queryVectorizer with the corpus pivot learned during Fit, if any
+	queryIdf    []float64        // Query IDF score for each vocabulary term, with queryVectorizer
+}
+
+// ModelOption is a functional option for configuring Model.
+type ModelOption func(*Model)
+
+// NewModel creates a new, unfitted TF-IDF model with the specified options.
+// By default, it uses a vectorizer created with NewTfIdfVectorizer (L2 normalization)
+// and applies IDF smoothing, like scikit-learn does.
+//
+// Example:
+//
+//	model := NewModel() // L2 normalization, smoothed IDF
+//	model := NewModel(WithSmoothing(false), WithVectorizer(NewTfIdfVectorizer(WithNormLevel(L1Norm))))
+func NewModel(opts ...ModelOption) *Model {
+	m := &Model{
+		vectorizer: NewTfIdfVectorizer(),
+		idfScheme:  SmoothIdf,
+		minDf:      dfBound{value: 1},
+		maxDf:      dfBound{value: 1, proportion: true},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// WithVectorizer sets the TF-IDF vectorizer used by the model to weight and normalize document vectors.
+//
+// Example:
+//
+//	model := NewModel(WithVectorizer(NewTfIdfVectorizer(WithNormLevel(NoNorm))))
+func WithVectorizer(v *TfIdfVectorizer) ModelOption {
+	return func(m *Model) {
+		m.vectorizer = v
+	}
+}
+
+// WithSmoothing sets whether add-one smoothing is applied when computing the IDF vector,
+// selecting SmoothIdf or StandardIdf. See Idf for the formulas used in both cases,
+// and WithIdfScheme for other formulas.
+func WithSmoothing(smoothing bool) ModelOption {
+	return func(m *Model) {
+		m.idfScheme = StandardIdf
+		if smoothing {
+			m.idfScheme = SmoothIdf
+		}
+	}
+}
+
+// Fit learns the vocabulary, the document frequencies and the IDF vector from a tokenized corpus.
+// Any previously fitted state is replaced.
+//
+// Parameters:
+//   - tokens: Tokenized documents where tokens[i] contains all tokens for document i
+//
+// Returns:
+//   - err: Error if the corpus is empty, contains no tokens at all, if no term survives pruning,
+//     or if the IDF scheme is invalid
+//
+// The vocabulary is sorted alphabetically, consistently with token.Tokenizer.
+// Terms outside the document frequency bounds, or beyond the max features cap, are left out
+// of the vocabulary and reported by PrunedTerms.
+func (m *Model) Fit(tokens [][]string) error {
+	return m.FitContext(context.Background(), tokens)
+}
+
+// FitContext is like Fit, but checks ctx between documents and returns ctx.Err() as soon as ctx is done,
+// leaving the model unchanged.
+func (m *Model) FitContext(ctx context.Context, tokens [][]string) error {
+	if len(tokens) == 0 {
+		return errors.New("empty corpus")
+	}
+
+	// Build the vocabulary and count document frequencies in a single pass,
+	// counting each shard of documents separately, then merging the shard counts
+	shards := make([]*frequencies, parallel.Workers(m.vectorizer.Workers, len(tokens)))
+	err := parallel.For(m.vectorizer.Workers, len(tokens), func(shard, lo, hi int) error {
+		shards[shard] = m.newFrequencies()
+		for _, doc := range tokens[lo:hi] {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			shards[shard].add(doc)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	freq := shards[0]
+	for _, shard := range shards[1:] {
+		freq.merge(shard)
+	}
+	return m.fitFrequencies(ctx, freq.df, freq.cf, freq.nDocs, func(index map[string]int) (*SparseMatrix, error) {
+		return tfSparse(ctx, index, len(index), tokens, m.vectorizer.Workers)
+	})
+}
+
+// frequencies accumulates the document frequencies of the terms of a corpus and, if needed,
+// their corpus frequencies, one document at a time.
+type frequencies struct {
+	df    map[string]int      // Term -> number of documents containing it
+	cf    map[string]int      // Term -> number of occurrences, nil unless ranking terms for max features
+	nDocs int                 // Number of documents added
+	seen  map[string]struct{} // Terms of the current document, reused across documents
+}
+
+// newFrequencies returns empty frequencies, counting corpus frequencies only with max features.
+func (m *Model) newFrequencies() *frequencies {
+	f := &frequencies{df: make(map[string]int), seen: make(map[string]struct{})}
+	if m.maxFeatures > 0 {
+		f.cf = make(map[string]int)
+	}
+	return f
+}
+
+// merge adds the counts of o.
+func (f *frequencies) merge(o *frequencies) {
+	f.nDocs += o.nDocs
+	for term, count := range o.df {
+		f.df[term] += count
+	}
+	for term, count := range o.cf {
+		f.cf[term] += count
+	}
+}
+
+// add counts the terms of a document.
+func (f *frequencies) add(doc []string) {
+	f.nDocs++
+	clear(f.seen)
+	for _, term := range doc {
+		if f.cf != nil {
+			f.cf[term]++
+		}
+		if _, found := f.seen[term]; found {
+			continue
+		}
+		f.seen[term] = struct{}{}
+		f.df[term]++
+	}
+}
+
+// fitFrequencies learns the vocabulary and the IDF vector from the document frequencies
+// and, with max features, the corpus frequencies of the terms of nDocs documents.
+// counts returns the term count matrix of the documents against the learned vocabulary index;
+// it is only called to learn the corpus pivot of pivoted normalizations, and may be nil otherwise.
+// It returns ctx.Err() if ctx is done before the corpus pivot is learned.
+func (m *Model) fitFrequencies(ctx context.Context, dfMap, cfMap map[string]int, nDocs int, counts func(index map[string]int) (*SparseMatrix, error)) error {
+	if len(dfMap) == 0 {
+		return errors.New("empty vocabulary")
+	}
+
+	vocabulary, pruned, err := m.prune(dfMap, cfMap, nDocs)
+	if err != nil {
+		return err
+	}
+
+	index := make(map[string]int, len(vocabulary))
+	df := make([]int, len(vocabulary))
+	for j, term := range vocabulary {
+		index[term] = j
+		df[j] = dfMap[term]
+	}
+	idf, err := IdfFromDf(df, nDocs, m.idfScheme)
+	if err != nil {
+		return err
+	}
+	var tf *SparseMatrix
+	for _, v := range []*TfIdfVectorizer{m.vectorizer, m.queryVectorizer} {
+		if v != nil && v.pivoted() && v.Pivot == 0 && tf == nil {
+			if tf, err = counts(index); err != nil {
+				return err
+			}
+		}
+	}
+	fitted, err := m.vectorizer.withFittedPivot(ctx, tf, idf)
+	if err != nil {
+		return err
+	}
+	var fittedQuery *TfIdfVectorizer
+	var queryIdf []float64
+	if m.queryVectorizer != nil {
+		if queryIdf, err = IdfFromDf(df, nDocs, m.queryIdfScheme); err != nil {
+			return err
+		}
+		if fittedQuery, err = m.queryVectorizer.withFittedPivot(ctx, tf, queryIdf); err != nil {
+			return err
+		}
+	}
+
+	m.vocabulary = vocabulary
+	m.pruned = pruned
+	m.index = index
+	m.df = df
+	m.nDocs = nDocs
+	m.idf = idf
+	m.fitted = fitted
+	m.fittedQuery = fittedQuery
+	m.queryIdf = queryIdf
+	return nil
+}
+
+// Transform vectorizes tokenized documents against the fitted vocabulary and IDF vector.
+// Tokens that are not part of the vocabulary are ignored.
+//
+// Parameters:
+//   - tokens: Tokenized documents where tokens[i] contains all tokens for document i
+//
+// Returns:
+//   - tfIdfMat: TF-IDF matrix [documents][terms] normalized according to the model's vectorizer
+//   - err: ErrNotFitted if Fit has not been called, or an error from the vectorizer
+//
+// The matrix is computed in sparse form and converted to dense; use TransformSparse
+// to avoid allocating documents × vocabulary values on large vocabularies.
+func (m *Model) Transform(tokens [][]string) (tfIdfMat [][]float64, err error) {
+	return m.TransformContext(context.Background(), tokens)
+}
+
+// TransformContext is like Transform, but checks ctx between documents and returns ctx.Err()
+// as soon as ctx is done.
+func (m *Model) TransformContext(ctx context.Context, tokens [][]string) (tfIdfMat [][]float64, err error) {
+	sparse, err := m.TransformSparseContext(ctx, tokens)
+	if err != nil {
+		return nil, err
+	}
+	return sparse.Dense(), nil
+}
+
+// TransformSparse is like Transform, but returns the TF-IDF matrix in CSR format.
+func (m *Model) TransformSparse(tokens [][]string) (*SparseMatrix, error) {
+	return m.TransformSparseContext(context.Background(), tokens)
+}
+
+// TransformSparseContext is like TransformContext, but returns the TF-IDF matrix in CSR format.
+func (m *Model) TransformSparseContext(ctx context.Context, tokens [][]string) (*SparseMatrix, error) {
+	if !m.Fitted() {
+		return nil, ErrNotFitted
+	}
+	return m.transform(ctx, m.fitted, m.idf, tokens)
+}
+
+// TransformQuery is like Transform, but weights the documents with the query scheme set by WithSmart.
+// Without a query scheme, it is equivalent to Transform.
+func (m *Model) TransformQuery(tokens [][]string) (tfIdfMat [][]float64, err error) {
+	return m.TransformQueryContext(context.Background(), tokens)
+}
+
+// TransformQueryContext is like TransformQuery, but checks ctx between documents and returns ctx.Err()
+// as soon as ctx is done.
+func (m *Model) TransformQueryContext(ctx context.Context, tokens [][]string) (tfIdfMat [][]float64, err error) {
+	sparse, err := m.TransformQuerySparseContext(ctx, tokens)
+	if err != nil {
+		return nil, err
+	}
+	return sparse.Dense(), nil
+}
+
+// TransformQuerySparse is like TransformQuery, but returns the TF-IDF matrix in CSR format.
+func (m *Model) TransformQuerySparse(tokens [][]string) (*SparseMatrix, error) {
+	return m.TransformQuerySparseContext(context.Background(), tokens)
+}
+
+// TransformQuerySparseContext is like TransformQueryContext, but returns the TF-IDF matrix in CSR format.
+func (m *Model) TransformQuerySparseContext(ctx context.Context, tokens [][]string) (*SparseMatrix, error) {
+	if m.fittedQuery == nil {
+		return m.TransformSparseContext(ctx, tokens)
+	}
+	return m.transform(ctx, m.fittedQuery, m.queryIdf, tokens)
+}
+
+// transform counts the terms of the documents and weights them with the given vectorizer and IDF vector.
+func (m *Model) transform(ctx context.Context, v *TfIdfVectorizer, idf []float64, tokens [][]string) (*SparseMatrix, error) {
+	tf, err := tfSparse(ctx, m.index, len(m.vocabulary), tokens, v.Workers)
+	if err != nil {
+		return nil, err
+	}
+	return v.TfIdfSparseContext(ctx, tf, idf)
+}
+
+// FitTransform fits the model on a tokenized corpus and returns its TF-IDF matrix.
+// It is equivalent to calling Fit followed by Transform on the same tokens.
+func (m *Model) FitTransform(tokens [][]string) (tfIdfMat [][]float64, err error) {
+	return m.FitTransformContext(context.Background(), tokens)
+}
+
+// FitTransformContext is like FitTransform, but checks ctx between documents and returns ctx.Err()
+// as soon as ctx is done.
+func (m *Model) FitTransformContext(ctx context.Context, tokens [][]string) (tfIdfMat [][]float64, err error) {
+	if err := m.FitContext(ctx, tokens); err != nil {
+		return nil, err
+	}
+	return m.TransformContext(ctx, tokens)
+}
+
+// FitTransformSparse is like FitTransform, but returns the TF-IDF matrix in CSR format.
+func (m *Model) FitTransformSparse(tokens [][]string) (*SparseMatrix, error) {
+	return m.FitTransformSparseContext(context.Background(), tokens)
+}
+
+// FitTransformSparseContext is like FitTransformContext, but returns the TF-IDF matrix in CSR format.
+func (m *Model) FitTransformSparseContext(ctx context.Context, tokens [][]string) (*SparseMatrix, error) {
+	if err := m.FitContext(ctx, tokens); err != nil {
+		return nil, err
+	}
+	return m.TransformSparseContext(ctx, tokens)
+}
+
+// Fitted reports whether the model has been fitted.
+func (m *Model) Fitted() bool {
+	return m.vocabulary != nil
+}
+
+// Vocabulary returns the ordered list of terms learned during Fit.
+// The returned slice must not be modified.
+func (m *Model) Vocabulary() []string {
+	return m.vocabulary
+}
+
+// PrunedTerms returns the terms seen during Fit but left out of the vocabulary by the document
+// frequency bounds or the max features cap, sorted alphabetically, like scikit-learn stop_words_.
+// The returned slice must not be modified.
+func (m *Model) PrunedTerms() []string {
+	return m.pruned
+}
+
+// TermIndex returns the position of term in the vocabulary, and whether it was found.
+func (m *Model) TermIndex(term string) (int, bool) {
+	j, found := m.index[term]
+	return j, found
+}
+
+// DocumentFrequencies returns, for each vocabulary term, the number of fitted documents containing it,
+// or nil for a model imported with ImportSklearn. The returned slice must not be modified.
+func (m *Model) DocumentFrequencies() []int {
+	return m.df
+}
+
+// Idf returns the IDF vector learned during Fit, aligned with Vocabulary.
+// The returned slice must not be modified.
+func (m *Model) Idf() []float64 {
+	return m.idf
+}
+
+// NumDocuments returns the number of documents the model was fitted on, or 0 for a model imported with ImportSklearn.
+func (m *Model) NumDocuments() int {
+	return m.nDocs
+}
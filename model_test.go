@@ -0,0 +1,245 @@
+package tfidf
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+func TestModel_Fit(t *testing.T) {
+	tokens := [][]string{
+		{"the", "cat", "sat"},
+		{"the", "dog", "sat", "the"},
+		{"a", "cat"},
+	}
+
+	m := NewModel()
+	if err := m.Fit(tokens); err != nil {
+		t.Fatalf("Fit error: %v", err)
+	}
+
+	wantVocab := []string{"a", "cat", "dog", "sat", "the"}
+	if !slices.Equal(m.Vocabulary(), wantVocab) {
+		t.Errorf("vocabulary: got %v, want %v", m.Vocabulary(), wantVocab)
+	}
+	wantDf := []int{1, 2, 1, 2, 2}
+	if !slices.Equal(m.DocumentFrequencies(), wantDf) {
+		t.Errorf("df: got %v, want %v", m.DocumentFrequencies(), wantDf)
+	}
+	if m.NumDocuments() != 3 {
+		t.Errorf("documents: got %d, want 3", m.NumDocuments())
+	}
+	if j, found := m.TermIndex("sat"); !found || j != 3 {
+		t.Errorf("TermIndex(sat): got %d, %v", j, found)
+	}
+
+	// The model must agree with the free-standing Idf function
+	wantIdf := Idf(wantVocab, tokens, true)
+	if !almostEqualSlices(m.Idf(), wantIdf, tol) {
+		t.Errorf("idf: got %v, want %v", m.Idf(), wantIdf)
+	}
+}
+
+func TestModel_Transform(t *testing.T) {
+	tokens := [][]string{
+		{"this", "is", "a", "sample", "document"},
+		{"this", "document", "is", "another", "example"},
+		{"and", "this", "is", "a", "different", "one"},
+	}
+
+	m := NewModel()
+	got, err := m.FitTransform(tokens)
+	if err != nil {
+		t.Fatalf("FitTransform error: %v", err)
+	}
+
+	// Compare with the free-standing pipeline
+	vocab := m.Vocabulary()
+	vectorizer := NewTfIdfVectorizer()
+	want, err := vectorizer.TfIdf(Tf(vocab, tokens), Idf(vocab, tokens, true))
+	if err != nil {
+		t.Fatalf("TfIdf error: %v", err)
+	}
+	for i := range want {
+		if !almostEqualSlices(got[i], want[i], tol) {
+			t.Errorf("row %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	// Unknown terms are ignored when transforming new documents
+	query, err := m.Transform([][]string{{"sample", "unknown"}})
+	if err != nil {
+		t.Fatalf("Transform error: %v", err)
+	}
+	j, _ := m.TermIndex("sample")
+	for k, val := range query[0] {
+		if k == j && val != 1 {
+			t.Errorf("sample weight: got %v, want 1", val)
+		}
+		if k != j && val != 0 {
+			t.Errorf("term %q weight: got %v, want 0", vocab[k], val)
+		}
+	}
+}
+
+func TestModel_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens [][]string
+	}{
+		{name: "Empty corpus", tokens: [][]string{}},
+		{name: "Empty vocabulary", tokens: [][]string{{}, {}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel()
+			if err := m.Fit(tt.tokens); err == nil {
+				t.Errorf("Fit() expected error")
+			}
+			if m.Fitted() {
+				t.Errorf("model should not be fitted")
+			}
+		})
+	}
+
+	t.Run("Transform before Fit", func(t *testing.T) {
+		_, err := NewModel().Transform([][]string{{"a"}})
+		if !errors.Is(err, ErrNotFitted) {
+			t.Errorf("Transform() error = %v, want %v", err, ErrNotFitted)
+		}
+	})
+}
+
+func TestModel_Context(t *testing.T) {
+	tokens := shardedCorpus()
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context, m *Model) error
+	}{
+		{name: "FitContext", call: func(ctx context.Context, m *Model) error {
+			return m.FitContext(ctx, tokens)
+		}},
+		{name: "FitTransformContext", call: func(ctx context.Context, m *Model) error {
+			_, err := m.FitTransformContext(ctx, tokens)
+			return err
+		}},
+		{name: "FitTransformSparseContext", call: func(ctx context.Context, m *Model) error {
+			_, err := m.FitTransformSparseContext(ctx, tokens)
+			return err
+		}},
+		{name: "FitStreamContext", call: func(ctx context.Context, m *Model) error {
+			return m.FitStreamContext(ctx, TokenIteratorFunc(func() ([]string, error) { return []string{"a"}, nil }))
+		}},
+		{name: "FitContext with workers", call: func(ctx context.Context, m *Model) error {
+			m.vectorizer = NewTfIdfVectorizer(WithNormLevel(PivotedNorm), WithWorkers(3))
+			return m.FitContext(ctx, tokens)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel()
+			if err := tt.call(canceled, m); !errors.Is(err, context.Canceled) {
+				t.Errorf("error = %v, want %v", err, context.Canceled)
+			}
+			if m.Fitted() {
+				t.Error("model should not be fitted")
+			}
+		})
+	}
+
+	t.Run("TransformContext", func(t *testing.T) {
+		m := NewModel()
+		want, err := m.FitTransform(tokens)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := m.TransformContext(context.Background(), tokens)
+		if err != nil {
+			t.Fatalf("TransformContext() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Error("TransformContext() differs from Transform()")
+		}
+		if _, err := m.TransformContext(canceled, tokens); !errors.Is(err, context.Canceled) {
+			t.Errorf("TransformContext() error = %v, want %v", err, context.Canceled)
+		}
+		if _, err := m.TransformQueryContext(canceled, tokens); !errors.Is(err, context.Canceled) {
+			t.Errorf("TransformQueryContext() error = %v, want %v", err, context.Canceled)
+		}
+	})
+
+	// The terms of every document are counted before any is weighted, so a context done
+	// after len(tokens) checks is only seen by the weighting and normalization passes
+	t.Run("cancel after counting", func(t *testing.T) {
+		m := NewModel(WithSmart(SmartScheme{}, SmartScheme{}))
+		if err := m.Fit(tokens); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m.TransformContext(newCountdownContext(len(tokens)), tokens); !errors.Is(err, context.Canceled) {
+			t.Errorf("TransformContext() error = %v, want %v", err, context.Canceled)
+		}
+		if _, err := m.TransformQueryContext(newCountdownContext(len(tokens)), tokens); !errors.Is(err, context.Canceled) {
+			t.Errorf("TransformQueryContext() error = %v, want %v", err, context.Canceled)
+		}
+	})
+
+	// Fitting checks every document once to count document frequencies and once to count terms,
+	// then learns the corpus pivot by weighting every document and averaging their statistics
+	t.Run("cancel while learning the pivot", func(t *testing.T) {
+		for _, checks := range []int{2 * len(tokens), 3 * len(tokens)} {
+			m := NewModel(WithVectorizer(NewTfIdfVectorizer(WithNormLevel(PivotedNorm), WithWorkers(3))))
+			if err := m.FitContext(newCountdownContext(checks), tokens); !errors.Is(err, context.Canceled) {
+				t.Errorf("%d checks: FitContext() error = %v, want %v", checks, err, context.Canceled)
+			}
+			if m.Fitted() {
+				t.Errorf("%d checks: model should not be fitted", checks)
+			}
+		}
+	})
+
+	t.Run("cancel while streaming", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		n := 0
+		docs := TokenIteratorFunc(func() ([]string, error) {
+			if n++; n == 3 {
+				cancel()
+			}
+			return []string{"a"}, nil
+		})
+		if err := NewModel().FitStreamContext(ctx, docs); !errors.Is(err, context.Canceled) {
+			t.Errorf("FitStreamContext() error = %v, want %v", err, context.Canceled)
+		}
+		if n != 3 {
+			t.Errorf("FitStreamContext() read %d documents after cancellation", n-3)
+		}
+	})
+}
+
+// countdownContext is a context that is done once Err has been called a given number of times,
+// to cancel a call at a given point of its processing.
+type countdownContext struct {
+	context.Context
+	checks atomic.Int64
+}
+
+func newCountdownContext(checks int) *countdownContext {
+	ctx := &countdownContext{Context: context.Background()}
+	ctx.checks.Store(int64(checks))
+	return ctx
+}
+
+func (c *countdownContext) Err() error {
+	if c.checks.Add(-1) < 0 {
+		return context.Canceled
+	}
+	return nil
+}
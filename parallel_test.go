@@ -0,0 +1,67 @@
+package tfidf
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// shardedCorpus returns a corpus large enough to be split across several workers.
+func shardedCorpus() [][]string {
+	words := []string{"alpha", "beta", "gamma", "delta", "epsilon", "zeta", "eta", "theta", "iota", "kappa"}
+	tokens := make([][]string, 101)
+	for i := range tokens {
+		for k := 0; k < i%13; k++ {
+			tokens[i] = append(tokens[i], words[(i*k+k)%len(words)], fmt.Sprintf("term%d", (i+k)%17))
+		}
+	}
+	return tokens
+}
+
+func TestWorkers_SameOutput(t *testing.T) {
+	tokens := shardedCorpus()
+	vocabulary := NewModel()
+	if err := vocabulary.Fit(tokens); err != nil {
+		t.Fatal(err)
+	}
+	vocab := vocabulary.Vocabulary()
+
+	run := func(workers int) (any, error) {
+		opts := []TfIdfOption{WithWorkers(workers), WithTfScheme(SublinearTf)}
+		v := NewTfIdfVectorizer(opts...)
+		dense, err := NewTfIdfVectorizer(append(opts, WithNormLevel(PivotedNorm))...).TfIdf(v.Tf(vocab, tokens), v.Idf(vocab, tokens, true))
+		if err != nil {
+			return nil, err
+		}
+		sparse, err := v.TfIdfSparse(v.TfSparse(vocab, tokens), v.Idf(vocab, tokens, false))
+		if err != nil {
+			return nil, err
+		}
+		hashed, err := NewHashingVectorizer(WithNumFeatures(64), WithHashingWorkers(workers)).Transform(tokens)
+		if err != nil {
+			return nil, err
+		}
+		model := NewModel(WithVectorizer(NewTfIdfVectorizer(append(opts, WithNormLevel(PivotedUniqueNorm))...)), WithMaxFeatures(12))
+		fitted, err := model.FitTransformSparse(tokens)
+		if err != nil {
+			return nil, err
+		}
+		return []any{dense, sparse, fitted, model.Vocabulary(), model.DocumentFrequencies(), model.Idf(), v.Df(vocab, tokens), hashed}, nil
+	}
+
+	want, err := run(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, workers := range []int{0, 2, 7, 500} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			got, err := run(workers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Error("output differs from the sequential output")
+			}
+		})
+	}
+}
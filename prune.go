@@ -0,0 +1,128 @@
+package tfidf
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+)
+
+// dfBound is a document frequency threshold, either an absolute number of documents
+// or a proportion of the fitted corpus.
+type dfBound struct {
+	value      float64
+	proportion bool
+}
+
+// limit returns the threshold as a number of documents for a corpus of nDocs documents.
+func (b dfBound) limit(nDocs int) float64 {
+	if b.proportion {
+		return b.value * float64(nDocs)
+	}
+	return b.value
+}
+
+// valid reports whether the threshold is non-negative, and at most 1 if it is a proportion.
+func (b dfBound) valid() bool {
+	return b.value >= 0 && (!b.proportion || b.value <= 1)
+}
+
+// WithMinDf drops from the vocabulary the terms appearing in fewer than count documents,
+// like scikit-learn min_df given as an integer. Defaults to 1, keeping every term.
+//
+// Example:
+//
+//	model := NewModel(WithMinDf(2)) // drop hapax legomena
+func WithMinDf(count int) ModelOption {
+	return func(m *Model) {
+		m.minDf = dfBound{value: float64(count)}
+	}
+}
+
+// WithMinDfProportion drops from the vocabulary the terms appearing in less than
+// the given proportion of documents, in [0, 1], like scikit-learn min_df given as a float.
+func WithMinDfProportion(proportion float64) ModelOption {
+	return func(m *Model) {
+		m.minDf = dfBound{value: proportion, proportion: true}
+	}
+}
+
+// WithMaxDf drops from the vocabulary the terms appearing in more than count documents,
+// like scikit-learn max_df given as an integer.
+func WithMaxDf(count int) ModelOption {
+	return func(m *Model) {
+		m.maxDf = dfBound{value: float64(count)}
+	}
+}
+
+// WithMaxDfProportion drops from the vocabulary the terms appearing in more than
+// the given proportion of documents, in [0, 1], like scikit-learn max_df given as a float.
+// Defaults to 1, keeping every term.
+//
+// Example:
+//
+//	model := NewModel(WithMaxDfProportion(0.9)) // drop corpus-specific stop words
+func WithMaxDfProportion(proportion float64) ModelOption {
+	return func(m *Model) {
+		m.maxDf = dfBound{value: proportion, proportion: true}
+	}
+}
+
+// WithMaxFeatures caps the vocabulary to the n terms with the highest frequency across the corpus,
+// like scikit-learn max_features. Ties are broken alphabetically. It is applied after the
+// document frequency bounds. Defaults to 0, meaning no cap.
+func WithMaxFeatures(n int) ModelOption {
+	return func(m *Model) {
+		m.maxFeatures = n
+	}
+}
+
+// prune splits the terms of a corpus into the vocabulary to keep and the pruned terms,
+// according to the document frequency bounds and the max features cap of the model.
+// Both returned slices are sorted alphabetically.
+//
+// Parameters:
+//   - dfMap: Document frequency of every term of the corpus
+//   - cfMap: Frequency of every term across the corpus, only needed when max features is set
+//   - nDocs: Number of documents of the corpus
+//
+// Returns:
+//   - vocabulary: Terms kept
+//   - pruned: Terms removed
+//   - err: Error if the bounds are invalid or if no term remains
+func (m *Model) prune(dfMap, cfMap map[string]int, nDocs int) (vocabulary, pruned []string, err error) {
+	if !m.minDf.valid() || !m.maxDf.valid() || m.maxFeatures < 0 {
+		return nil, nil, errors.New("invalid pruning options")
+	}
+	minCount, maxCount := m.minDf.limit(nDocs), m.maxDf.limit(nDocs)
+	if maxCount < minCount {
+		return nil, nil, errors.New("max document frequency corresponds to fewer documents than min document frequency")
+	}
+
+	vocabulary = make([]string, 0, len(dfMap))
+	for term, df := range dfMap {
+		if float64(df) < minCount || float64(df) > maxCount {
+			pruned = append(pruned, term)
+			continue
+		}
+		vocabulary = append(vocabulary, term)
+	}
+
+	if m.maxFeatures > 0 && len(vocabulary) > m.maxFeatures {
+		// Rank by decreasing corpus frequency, then alphabetically
+		slices.SortFunc(vocabulary, func(a, b string) int {
+			if c := cmp.Compare(cfMap[b], cfMap[a]); c != 0 {
+				return c
+			}
+			return cmp.Compare(a, b)
+		})
+		pruned = append(pruned, vocabulary[m.maxFeatures:]...)
+		vocabulary = vocabulary[:m.maxFeatures]
+	}
+
+	if len(vocabulary) == 0 {
+		return nil, nil, errors.New("no terms remain after pruning")
+	}
+	slices.Sort(vocabulary)
+	slices.Sort(pruned)
+	return vocabulary, pruned, nil
+}
@@ -0,0 +1,112 @@
+package tfidf
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestModel_Prune(t *testing.T) {
+	tokens := [][]string{
+		{"the", "cat", "sat", "on", "the", "mat"},
+		{"the", "dog", "sat", "on", "the", "log"},
+		{"the", "cat", "ate", "the", "fish"},
+		{"a", "cat", "and", "a", "dog"},
+	}
+
+	tests := []struct {
+		name       string
+		opts       []ModelOption
+		wantVocab  []string
+		wantPruned []string
+		wantErr    bool
+	}{
+		{
+			name:      "No pruning by default",
+			wantVocab: []string{"a", "and", "ate", "cat", "dog", "fish", "log", "mat", "on", "sat", "the"},
+		},
+		{
+			name:       "Min df count",
+			opts:       []ModelOption{WithMinDf(2)},
+			wantVocab:  []string{"cat", "dog", "on", "sat", "the"},
+			wantPruned: []string{"a", "and", "ate", "fish", "log", "mat"},
+		},
+		{
+			name:       "Min df proportion",
+			opts:       []ModelOption{WithMinDfProportion(0.7)},
+			wantVocab:  []string{"cat", "the"},
+			wantPruned: []string{"a", "and", "ate", "dog", "fish", "log", "mat", "on", "sat"},
+		},
+		{
+			name:       "Max df count",
+			opts:       []ModelOption{WithMaxDf(2)},
+			wantVocab:  []string{"a", "and", "ate", "dog", "fish", "log", "mat", "on", "sat"},
+			wantPruned: []string{"cat", "the"},
+		},
+		{
+			name:       "Max df proportion",
+			opts:       []ModelOption{WithMinDf(2), WithMaxDfProportion(0.5)},
+			wantVocab:  []string{"dog", "on", "sat"},
+			wantPruned: []string{"a", "and", "ate", "cat", "fish", "log", "mat", "the"},
+		},
+		{
+			// Corpus frequencies: the 6, cat 3, a/dog/on/sat 2; ties broken alphabetically
+			name:       "Max features",
+			opts:       []ModelOption{WithMaxFeatures(3)},
+			wantVocab:  []string{"a", "cat", "the"},
+			wantPruned: []string{"and", "ate", "dog", "fish", "log", "mat", "on", "sat"},
+		},
+		{
+			name:       "Max features after df bounds",
+			opts:       []ModelOption{WithMaxDf(2), WithMaxFeatures(2)},
+			wantVocab:  []string{"a", "dog"},
+			wantPruned: []string{"and", "ate", "cat", "fish", "log", "mat", "on", "sat", "the"},
+		},
+		{
+			name:    "Nothing left",
+			opts:    []ModelOption{WithMinDf(5)},
+			wantErr: true,
+		},
+		{
+			name:    "Max df below min df",
+			opts:    []ModelOption{WithMinDf(3), WithMaxDf(2)},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid proportion",
+			opts:    []ModelOption{WithMaxDfProportion(1.5)},
+			wantErr: true,
+		},
+		{
+			name:    "Negative max features",
+			opts:    []ModelOption{WithMaxFeatures(-1)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(tt.opts...)
+			err := m.Fit(tokens)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Fit() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Fit error: %v", err)
+			}
+			if !slices.Equal(m.Vocabulary(), tt.wantVocab) {
+				t.Errorf("vocabulary: got %v, want %v", m.Vocabulary(), tt.wantVocab)
+			}
+			if !slices.Equal(m.PrunedTerms(), tt.wantPruned) {
+				t.Errorf("pruned: got %v, want %v", m.PrunedTerms(), tt.wantPruned)
+			}
+			// Pruning must not change the IDF of the remaining terms
+			wantIdf := Idf(m.Vocabulary(), tokens, true)
+			if !almostEqualSlices(m.Idf(), wantIdf, tol) {
+				t.Errorf("idf: got %v, want %v", m.Idf(), wantIdf)
+			}
+		})
+	}
+}
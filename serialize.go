@@ -0,0 +1,510 @@
+package tfidf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+var (
+	// ErrInvalidFormat is returned when loading data that is not a valid serialized model.
+	ErrInvalidFormat = errors.New("invalid model format")
+
+	// ErrUnsupportedVersion is returned when loading a model saved with a format version
+	// this package cannot read.
+	ErrUnsupportedVersion = errors.New("unsupported model format version")
+)
+
+const (
+	// formatVersion is the version of the serialized model formats written by this package.
+	// It must be increased on every incompatible change of the formats.
+	formatVersion = 1
+
+	// jsonFormat identifies the JSON serialization of a model.
+	jsonFormat = "tfidf-go/model"
+)
+
+// binaryMagic starts the binary serialization of a model.
+var binaryMagic = []byte("TFIDFGO\x00")
+
+// vectorizerState is the serialized form of a vectorizer and of the IDF scheme it is used with.
+type vectorizerState struct {
+	NormLevel   NLevel    `json:"norm_level"`
+	TfScheme    TfScheme  `json:"tf_scheme"`
+	IdfScheme   IdfScheme `json:"idf_scheme"`
+	Slope       float64   `json:"slope"`
+	Pivot       float64   `json:"pivot"`
+	FittedPivot float64   `json:"fitted_pivot"` // Pivot learned from the corpus, if any
+}
+
+// modelState is the serialized form of a fitted Model, shared by the binary and JSON formats.
+type modelState struct {
+	Format    string           `json:"format"`
+	Version   int              `json:"version"`
+	Tokenizer json.RawMessage  `json:"tokenizer,omitempty"`
+	Document  vectorizerState  `json:"document"`
+	Query     *vectorizerState `json:"query,omitempty"`
+
+	MinDf           float64 `json:"min_df"`
+	MinDfProportion bool    `json:"min_df_proportion"`
+	MaxDf           float64 `json:"max_df"`
+	MaxDfProportion bool    `json:"max_df_proportion"`
+	MaxFeatures     int     `json:"max_features"`
+
+	Imported     bool      `json:"imported,omitempty"` // Imported from scikit-learn, without document frequencies
+	NumDocuments int       `json:"num_documents"`
+	Vocabulary   []string  `json:"vocabulary"`
+	Df           []int     `json:"df"`
+	Idf          []float64 `json:"idf"`
+	QueryIdf     []float64 `json:"query_idf,omitempty"`
+	Pruned       []string  `json:"pruned,omitempty"`
+}
+
+// Save writes the fitted state of the model to w in a compact, versioned binary format:
+// the vocabulary, the document frequencies, the IDF vectors and the weighting options,
+// so that LoadModel restores a model vectorizing documents identically without refitting.
+//
+// Parameters:
+//   - w: Destination of the serialized model
+//   - tokenizer: Optional tokenizer whose configuration is saved with the model, e.g. a *token.Tokenizer; may be nil
+//
+// Returns:
+//   - err: ErrNotFitted if Fit has not been called, or an error from the tokenizer or from w
+//
+// Example:
+//
+//	f, _ := os.Create("model.bin")
+//	defer f.Close()
+//	err := model.Save(f, tokenizer)
+func (m *Model) Save(w io.Writer, tokenizer json.Marshaler) error {
+	state, err := m.state(tokenizer)
+	if err != nil {
+		return err
+	}
+	bw := &binaryWriter{w: bufio.NewWriter(w)}
+	bw.raw(binaryMagic)
+	bw.uvarint(formatVersion)
+	bw.bytes(state.Tokenizer)
+	bw.vectorizer(state.Document)
+	bw.bool(state.Query != nil)
+	if state.Query != nil {
+		bw.vectorizer(*state.Query)
+	}
+	bw.float(state.MinDf)
+	bw.bool(state.MinDfProportion)
+	bw.float(state.MaxDf)
+	bw.bool(state.MaxDfProportion)
+	bw.varint(state.MaxFeatures)
+	bw.bool(state.Imported)
+	bw.varint(state.NumDocuments)
+	bw.uvarint(uint64(len(state.Vocabulary)))
+	for j, term := range state.Vocabulary {
+		bw.string(term)
+		bw.varint(state.Df[j])
+		bw.float(state.Idf[j])
+	}
+	for _, idf := range state.QueryIdf {
+		bw.float(idf)
+	}
+	bw.uvarint(uint64(len(state.Pruned)))
+	for _, term := range state.Pruned {
+		bw.string(term)
+	}
+	if bw.err != nil {
+		return bw.err
+	}
+	return bw.w.Flush()
+}
+
+// LoadModel reads a model written by Save from r.
+// If tokenizer is not nil, it is configured as the tokenizer saved with the model, e.g. a *token.Tokenizer.
+//
+// Returns:
+//   - model: The fitted model
+//   - err: ErrInvalidFormat if r does not hold a valid model, ErrUnsupportedVersion if it was
+//     written by an incompatible version, or an error from the tokenizer or from r
+//
+// Example:
+//
+//	f, _ := os.Open("model.bin")
+//	defer f.Close()
+//	var tokenizer token.Tokenizer
+//	model, err := LoadModel(f, &tokenizer)
+func LoadModel(r io.Reader, tokenizer json.Unmarshaler) (*Model, error) {
+	br := &binaryReader{r: bufio.NewReader(r)}
+	magic := br.raw(len(binaryMagic))
+	if br.err != nil || !bytes.Equal(magic, binaryMagic) {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidFormat)
+	}
+	if version := br.uvarint(); br.err == nil && version != formatVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+
+	state := modelState{Format: jsonFormat, Version: formatVersion}
+	state.Tokenizer = br.bytes()
+	state.Document = br.vectorizer()
+	if br.bool() {
+		query := br.vectorizer()
+		state.Query = &query
+	}
+	state.MinDf = br.float()
+	state.MinDfProportion = br.bool()
+	state.MaxDf = br.float()
+	state.MaxDfProportion = br.bool()
+	state.MaxFeatures = br.varint()
+	state.Imported = br.bool()
+	state.NumDocuments = br.varint()
+	n := br.uvarint()
+	for k := uint64(0); k < n && br.err == nil; k++ {
+		state.Vocabulary = append(state.Vocabulary, br.string())
+		state.Df = append(state.Df, br.varint())
+		state.Idf = append(state.Idf, br.float())
+	}
+	if state.Query != nil {
+		for k := uint64(0); k < n && br.err == nil; k++ {
+			state.QueryIdf = append(state.QueryIdf, br.float())
+		}
+	}
+	n = br.uvarint()
+	for k := uint64(0); k < n && br.err == nil; k++ {
+		state.Pruned = append(state.Pruned, br.string())
+	}
+	if br.err != nil {
+		return nil, br.err
+	}
+	return modelFromState(state, tokenizer)
+}
+
+// SaveJSON is like Save, but writes the model as JSON, which is larger but human readable
+// and easy to consume from other languages.
+func (m *Model) SaveJSON(w io.Writer, tokenizer json.Marshaler) error {
+	state, err := m.state(tokenizer)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(state)
+}
+
+// LoadModelJSON is like LoadModel, but reads a model written by SaveJSON.
+func LoadModelJSON(r io.Reader, tokenizer json.Unmarshaler) (*Model, error) {
+	var state modelState
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	if state.Format != jsonFormat {
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidFormat, state.Format)
+	}
+	if state.Version != formatVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, state.Version)
+	}
+	return modelFromState(state, tokenizer)
+}
+
+// state returns the serialized form of the fitted model and of the tokenizer configuration.
+func (m *Model) state(tokenizer json.Marshaler) (modelState, error) {
+	if !m.Fitted() {
+		return modelState{}, ErrNotFitted
+	}
+	state := modelState{
+		Format:          jsonFormat,
+		Version:         formatVersion,
+		Document:        newVectorizerState(m.vectorizer, m.fitted, m.idfScheme),
+		MinDf:           m.minDf.value,
+		MinDfProportion: m.minDf.proportion,
+		MaxDf:           m.maxDf.value,
+		MaxDfProportion: m.maxDf.proportion,
+		MaxFeatures:     m.maxFeatures,
+		NumDocuments:    m.nDocs,
+		Vocabulary:      m.vocabulary,
+		Df:              m.df,
+		Idf:             m.idf,
+		QueryIdf:        m.queryIdf,
+		Pruned:          m.pruned,
+	}
+	if m.df == nil {
+		// Models imported from scikit-learn have no document frequencies
+		state.Imported = true
+		state.Df = make([]int, len(m.vocabulary))
+	}
+	if m.queryVectorizer != nil {
+		query := newVectorizerState(m.queryVectorizer, m.fittedQuery, m.queryIdfScheme)
+		state.Query = &query
+	}
+	if tokenizer != nil {
+		config, err := tokenizer.MarshalJSON()
+		if err != nil {
+			return modelState{}, fmt.Errorf("cannot save tokenizer: %w", err)
+		}
+		// A nil tokenizer behind a non-nil interface marshals to null: nothing is saved
+		if !bytes.Equal(config, []byte("null")) {
+			state.Tokenizer = config
+		}
+	}
+	return state, nil
+}
+
+// newVectorizerState returns the serialized form of a configured vectorizer and of its fitted copy.
+func newVectorizerState(v, fitted *TfIdfVectorizer, idfScheme IdfScheme) vectorizerState {
+	return vectorizerState{
+		NormLevel:   v.NormLevel,
+		TfScheme:    v.TfScheme,
+		IdfScheme:   idfScheme,
+		Slope:       v.Slope,
+		Pivot:       v.Pivot,
+		FittedPivot: fitted.Pivot,
+	}
+}
+
+// vectorizers returns the configured vectorizer and its fitted copy from their serialized form.
+func (s vectorizerState) vectorizers() (v, fitted *TfIdfVectorizer) {
+	v = &TfIdfVectorizer{
+		NormLevel: s.NormLevel,
+		TfScheme:  s.TfScheme,
+		Slope:     s.Slope,
+		Pivot:     s.Pivot,
+	}
+	if s.FittedPivot == s.Pivot {
+		return v, v
+	}
+	f := *v
+	f.Pivot = s.FittedPivot
+	return v, &f
+}
+
+// validate checks that the weighting options are known to this package.
+func (s vectorizerState) validate() error {
+	switch {
+	case s.NormLevel < NoNorm || s.NormLevel > MaxNorm:
+		return fmt.Errorf("%w: invalid normalization level %d", ErrInvalidFormat, s.NormLevel)
+	case s.TfScheme < RawTf || s.TfScheme > LogAverageTf:
+		return fmt.Errorf("%w: invalid TF scheme %d", ErrInvalidFormat, s.TfScheme)
+	case s.IdfScheme < SmoothIdf || s.IdfScheme > NoIdf:
+		return fmt.Errorf("%w: invalid IDF scheme %d", ErrInvalidFormat, s.IdfScheme)
+	}
+	return nil
+}
+
+// modelFromState validates a serialized model and restores it, together with the tokenizer configuration.
+func modelFromState(state modelState, tokenizer json.Unmarshaler) (*Model, error) {
+	n := len(state.Vocabulary)
+	switch {
+	case n == 0:
+		return nil, fmt.Errorf("%w: empty vocabulary", ErrInvalidFormat)
+	case len(state.Df) != n || len(state.Idf) != n:
+		return nil, fmt.Errorf("%w: vocabulary, df and idf lengths don't match", ErrInvalidFormat)
+	case state.Query != nil && len(state.QueryIdf) != n:
+		return nil, fmt.Errorf("%w: vocabulary and query idf lengths don't match", ErrInvalidFormat)
+	case state.Imported && state.NumDocuments != 0, !state.Imported && state.NumDocuments <= 0:
+		return nil, fmt.Errorf("%w: invalid number of documents %d", ErrInvalidFormat, state.NumDocuments)
+	}
+
+	if err := state.Document.validate(); err != nil {
+		return nil, err
+	}
+	if state.Query != nil {
+		if err := state.Query.validate(); err != nil {
+			return nil, err
+		}
+	}
+	for j, df := range state.Df {
+		if df < 0 || (!state.Imported && df > state.NumDocuments) {
+			return nil, fmt.Errorf("%w: invalid df %d for term %q", ErrInvalidFormat, df, state.Vocabulary[j])
+		}
+	}
+	for _, idf := range [][]float64{state.Idf, state.QueryIdf} {
+		for j, val := range idf {
+			if math.IsNaN(val) || math.IsInf(val, 0) {
+				return nil, fmt.Errorf("%w: non-finite idf for term %q", ErrInvalidFormat, state.Vocabulary[j])
+			}
+		}
+	}
+
+	index := make(map[string]int, n)
+	for j, term := range state.Vocabulary {
+		if _, found := index[term]; found {
+			return nil, fmt.Errorf("%w: duplicate term %q", ErrInvalidFormat, term)
+		}
+		index[term] = j
+	}
+
+	if tokenizer != nil {
+		if len(state.Tokenizer) == 0 {
+			return nil, errors.New("model was saved without a tokenizer configuration")
+		}
+		if err := tokenizer.UnmarshalJSON(state.Tokenizer); err != nil {
+			return nil, fmt.Errorf("cannot load tokenizer: %w", err)
+		}
+	}
+
+	m := &Model{
+		idfScheme:   state.Document.IdfScheme,
+		minDf:       dfBound{value: state.MinDf, proportion: state.MinDfProportion},
+		maxDf:       dfBound{value: state.MaxDf, proportion: state.MaxDfProportion},
+		maxFeatures: state.MaxFeatures,
+		vocabulary:  state.Vocabulary,
+		pruned:      state.Pruned,
+		index:       index,
+		df:          state.Df,
+		idf:         state.Idf,
+		nDocs:       state.NumDocuments,
+	}
+	if state.Imported {
+		m.df = nil
+	}
+	m.vectorizer, m.fitted = state.Document.vectorizers()
+	if state.Query != nil {
+		m.queryVectorizer, m.fittedQuery = state.Query.vectorizers()
+		m.queryIdfScheme = state.Query.IdfScheme
+		m.queryIdf = state.QueryIdf
+	}
+	return m, nil
+}
+
+// binaryWriter writes the binary model format, keeping the first error encountered
+// so that the caller can check it once at the end.
+type binaryWriter struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+	err error
+}
+
+func (b *binaryWriter) raw(p []byte) {
+	if b.err == nil {
+		_, b.err = b.w.Write(p)
+	}
+}
+
+func (b *binaryWriter) uvarint(v uint64) {
+	b.raw(b.buf[:binary.PutUvarint(b.buf[:], v)])
+}
+
+func (b *binaryWriter) varint(v int) {
+	b.raw(b.buf[:binary.PutVarint(b.buf[:], int64(v))])
+}
+
+func (b *binaryWriter) float(v float64) {
+	binary.LittleEndian.PutUint64(b.buf[:8], math.Float64bits(v))
+	b.raw(b.buf[:8])
+}
+
+func (b *binaryWriter) bool(v bool) {
+	if v {
+		b.uvarint(1)
+		return
+	}
+	b.uvarint(0)
+}
+
+func (b *binaryWriter) bytes(p []byte) {
+	b.uvarint(uint64(len(p)))
+	b.raw(p)
+}
+
+func (b *binaryWriter) string(s string) {
+	b.bytes([]byte(s))
+}
+
+func (b *binaryWriter) vectorizer(s vectorizerState) {
+	b.varint(int(s.NormLevel))
+	b.varint(int(s.TfScheme))
+	b.varint(int(s.IdfScheme))
+	b.float(s.Slope)
+	b.float(s.Pivot)
+	b.float(s.FittedPivot)
+}
+
+// binaryReader reads the binary model format, keeping the first error encountered.
+// Once an error occurred, every read returns a zero value.
+type binaryReader struct {
+	r   *bufio.Reader
+	err error
+}
+
+// fail records err, reporting a truncated input as an invalid format.
+func (b *binaryReader) fail(err error) {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = fmt.Errorf("%w: unexpected end of data", ErrInvalidFormat)
+	}
+	b.err = err
+}
+
+func (b *binaryReader) raw(n int) []byte {
+	if b.err != nil {
+		return nil
+	}
+	p := make([]byte, n)
+	if _, err := io.ReadFull(b.r, p); err != nil {
+		b.fail(err)
+		return nil
+	}
+	return p
+}
+
+func (b *binaryReader) uvarint() uint64 {
+	if b.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(b.r)
+	if err != nil {
+		b.fail(err)
+	}
+	return v
+}
+
+func (b *binaryReader) varint() int {
+	if b.err != nil {
+		return 0
+	}
+	v, err := binary.ReadVarint(b.r)
+	if err != nil {
+		b.fail(err)
+	}
+	return int(v)
+}
+
+func (b *binaryReader) float() float64 {
+	p := b.raw(8)
+	if p == nil {
+		return 0
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(p))
+}
+
+func (b *binaryReader) bool() bool {
+	return b.uvarint() != 0
+}
+
+func (b *binaryReader) bytes() []byte {
+	n := b.uvarint()
+	if b.err != nil {
+		return nil
+	}
+	// Read in bounded chunks, so that a corrupted length cannot allocate more than the input holds.
+	var p []byte
+	for n > 0 && b.err == nil {
+		chunk := min(n, 1<<16)
+		p = append(p, b.raw(int(chunk))...)
+		n -= chunk
+	}
+	return p
+}
+
+func (b *binaryReader) string() string {
+	return string(b.bytes())
+}
+
+func (b *binaryReader) vectorizer() vectorizerState {
+	return vectorizerState{
+		NormLevel:   NLevel(b.varint()),
+		TfScheme:    TfScheme(b.varint()),
+		IdfScheme:   IdfScheme(b.varint()),
+		Slope:       b.float(),
+		Pivot:       b.float(),
+		FittedPivot: b.float(),
+	}
+}
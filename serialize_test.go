@@ -0,0 +1,308 @@
+package tfidf
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rioloc/tfidf-go/token"
+)
+
+// jsonConfig is a minimal tokenizer configuration for the serialization tests.
+type jsonConfig struct {
+	Lowercase bool `json:"lowercase"`
+}
+
+func (c *jsonConfig) MarshalJSON() ([]byte, error) {
+	type plain jsonConfig
+	return json.Marshal((*plain)(c))
+}
+
+func (c *jsonConfig) UnmarshalJSON(data []byte) error {
+	type plain jsonConfig
+	return json.Unmarshal(data, (*plain)(c))
+}
+
+func TestModel_SaveLoad(t *testing.T) {
+	tokens := [][]string{
+		{"this", "is", "a", "sample", "document"},
+		{"this", "document", "is", "another", "example"},
+		{"and", "this", "is", "a", "different", "one"},
+		{"example", "example", "one"},
+	}
+	queries := [][]string{{"sample", "example", "example", "unknown"}, {}}
+	doc, query, _ := ParseSmart("lnu.ltc")
+
+	tests := []struct {
+		name  string
+		model *Model
+	}{
+		{name: "Default", model: NewModel()},
+		{
+			name: "Weighting and pruning options",
+			model: NewModel(
+				WithVectorizer(NewTfIdfVectorizer(WithTfScheme(SublinearTf), WithNormLevel(PivotedNorm))),
+				WithIdfScheme(ProbabilisticIdf),
+				WithMinDf(2),
+				WithMaxDfProportion(0.9),
+			),
+		},
+		{name: "SMART", model: NewModel(WithSmart(doc, query))},
+	}
+
+	formats := []struct {
+		name string
+		save func(m *Model, w *bytes.Buffer, tokenizer json.Marshaler) error
+		load func(r *bytes.Buffer, tokenizer json.Unmarshaler) (*Model, error)
+	}{
+		{
+			name: "Binary",
+			save: func(m *Model, w *bytes.Buffer, tokenizer json.Marshaler) error { return m.Save(w, tokenizer) },
+			load: func(r *bytes.Buffer, tokenizer json.Unmarshaler) (*Model, error) { return LoadModel(r, tokenizer) },
+		},
+		{
+			name: "JSON",
+			save: func(m *Model, w *bytes.Buffer, tokenizer json.Marshaler) error { return m.SaveJSON(w, tokenizer) },
+			load: func(r *bytes.Buffer, tokenizer json.Unmarshaler) (*Model, error) { return LoadModelJSON(r, tokenizer) },
+		},
+	}
+
+	for _, tt := range tests {
+		if err := tt.model.Fit(tokens); err != nil {
+			t.Fatalf("%s: Fit error: %v", tt.name, err)
+		}
+		for _, format := range formats {
+			t.Run(tt.name+"/"+format.name, func(t *testing.T) {
+				var buf bytes.Buffer
+				if err := format.save(tt.model, &buf, &jsonConfig{Lowercase: true}); err != nil {
+					t.Fatalf("save error: %v", err)
+				}
+				var config jsonConfig
+				loaded, err := format.load(&buf, &config)
+				if err != nil {
+					t.Fatalf("load error: %v", err)
+				}
+				if !config.Lowercase {
+					t.Errorf("tokenizer configuration not restored")
+				}
+
+				if !slices.Equal(loaded.Vocabulary(), tt.model.Vocabulary()) ||
+					!slices.Equal(loaded.DocumentFrequencies(), tt.model.DocumentFrequencies()) ||
+					!slices.Equal(loaded.PrunedTerms(), tt.model.PrunedTerms()) ||
+					loaded.NumDocuments() != tt.model.NumDocuments() {
+					t.Errorf("fitted state not restored")
+				}
+				for _, docs := range [][][]string{tokens, queries} {
+					want, _ := tt.model.Transform(docs)
+					got, err := loaded.Transform(docs)
+					if err != nil {
+						t.Fatalf("Transform error: %v", err)
+					}
+					wantQuery, _ := tt.model.TransformQuery(docs)
+					gotQuery, err := loaded.TransformQuery(docs)
+					if err != nil {
+						t.Fatalf("TransformQuery error: %v", err)
+					}
+					for i := range want {
+						if !slices.Equal(got[i], want[i]) || !slices.Equal(gotQuery[i], wantQuery[i]) {
+							t.Errorf("row %d: got %v and %v, want %v and %v", i, got[i], gotQuery[i], want[i], wantQuery[i])
+						}
+					}
+				}
+
+				// Refitting the loaded model uses the restored options
+				if err := loaded.Fit(tokens); err != nil {
+					t.Fatalf("Fit error: %v", err)
+				}
+				if !slices.Equal(loaded.Idf(), tt.model.Idf()) {
+					t.Errorf("refit idf: got %v, want %v", loaded.Idf(), tt.model.Idf())
+				}
+			})
+		}
+	}
+}
+
+func TestModel_SaveLoad_Errors(t *testing.T) {
+	if err := NewModel().Save(&bytes.Buffer{}, nil); !errors.Is(err, ErrNotFitted) {
+		t.Errorf("Save() unfitted: got %v, want ErrNotFitted", err)
+	}
+	if err := NewModel().SaveJSON(&bytes.Buffer{}, nil); !errors.Is(err, ErrNotFitted) {
+		t.Errorf("SaveJSON() unfitted: got %v, want ErrNotFitted", err)
+	}
+
+	m := NewModel()
+	if err := m.Fit([][]string{{"cat", "sat"}, {"dog"}}); err != nil {
+		t.Fatalf("Fit error: %v", err)
+	}
+	var bin, js bytes.Buffer
+	if err := m.Save(&bin, nil); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	if err := m.SaveJSON(&js, nil); err != nil {
+		t.Fatalf("SaveJSON error: %v", err)
+	}
+	binary, text := bin.Bytes(), js.String()
+
+	// The version follows the 8 bytes magic header
+	newer := slices.Clone(binary)
+	newer[len(binaryMagic)] = formatVersion + 1
+
+	tests := []struct {
+		name    string
+		load    func() (*Model, error)
+		wantErr error
+	}{
+		{
+			name:    "Binary empty",
+			load:    func() (*Model, error) { return LoadModel(bytes.NewReader(nil), nil) },
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name:    "Binary wrong header",
+			load:    func() (*Model, error) { return LoadModel(strings.NewReader(text), nil) },
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name:    "Binary truncated",
+			load:    func() (*Model, error) { return LoadModel(bytes.NewReader(binary[:len(binary)-5]), nil) },
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name:    "Binary newer version",
+			load:    func() (*Model, error) { return LoadModel(bytes.NewReader(newer), nil) },
+			wantErr: ErrUnsupportedVersion,
+		},
+		{
+			name:    "JSON not a model",
+			load:    func() (*Model, error) { return LoadModelJSON(strings.NewReader(`{"vocabulary": ["a"]}`), nil) },
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name:    "JSON syntax",
+			load:    func() (*Model, error) { return LoadModelJSON(bytes.NewReader(binary), nil) },
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name: "JSON newer version",
+			load: func() (*Model, error) {
+				return LoadModelJSON(strings.NewReader(strings.Replace(text, `"version":1`, `"version":2`, 1)), nil)
+			},
+			wantErr: ErrUnsupportedVersion,
+		},
+		{
+			name: "JSON lengths mismatch",
+			load: func() (*Model, error) {
+				return LoadModelJSON(strings.NewReader(strings.Replace(text, `"df":[1,1,1]`, `"df":[1,1]`, 1)), nil)
+			},
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name: "JSON no documents",
+			load: func() (*Model, error) {
+				return LoadModelJSON(strings.NewReader(strings.Replace(text, `"num_documents":2`, `"num_documents":0`, 1)), nil)
+			},
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name: "JSON df above number of documents",
+			load: func() (*Model, error) {
+				return LoadModelJSON(strings.NewReader(strings.Replace(text, `"df":[1,1,1]`, `"df":[1,3,1]`, 1)), nil)
+			},
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name: "JSON invalid normalization level",
+			load: func() (*Model, error) {
+				return LoadModelJSON(strings.NewReader(strings.Replace(text, `"norm_level":2`, `"norm_level":99`, 1)), nil)
+			},
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name: "JSON invalid TF scheme",
+			load: func() (*Model, error) {
+				return LoadModelJSON(strings.NewReader(strings.Replace(text, `"tf_scheme":0`, `"tf_scheme":-1`, 1)), nil)
+			},
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name: "JSON invalid IDF scheme",
+			load: func() (*Model, error) {
+				return LoadModelJSON(strings.NewReader(strings.Replace(text, `"idf_scheme":0`, `"idf_scheme":42`, 1)), nil)
+			},
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name: "JSON negative df",
+			load: func() (*Model, error) {
+				return LoadModelJSON(strings.NewReader(strings.Replace(text, `"df":[1,1,1]`, `"df":[1,-1,1]`, 1)), nil)
+			},
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name: "Binary non-finite idf",
+			load: func() (*Model, error) {
+				corrupted := slices.Clone(binary)
+				// The idf of the last term is followed by the empty pruned list
+				nan := math.Float64bits(math.NaN())
+				for k := 0; k < 8; k++ {
+					corrupted[len(corrupted)-9+k] = byte(nan >> (8 * k))
+				}
+				return LoadModel(bytes.NewReader(corrupted), nil)
+			},
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name: "JSON duplicate term",
+			load: func() (*Model, error) {
+				return LoadModelJSON(strings.NewReader(strings.Replace(text, `"dog"`, `"cat"`, 1)), nil)
+			},
+			wantErr: ErrInvalidFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.load(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// A tokenizer cannot be restored from a model saved without one
+	if _, err := LoadModel(bytes.NewReader(binary), &jsonConfig{}); err == nil {
+		t.Errorf("LoadModel() expected error for missing tokenizer configuration")
+	}
+}
+
+func TestModel_Save_NilTokenizer(t *testing.T) {
+	m := NewModel()
+	if err := m.Fit([][]string{{"cat", "sat"}, {"dog"}}); err != nil {
+		t.Fatalf("Fit error: %v", err)
+	}
+
+	// A nil tokenizer pointer is not a nil interface, but is saved as no tokenizer
+	var tokenizer *token.Tokenizer
+	var charTokenizer *token.CharNGramTokenizer
+	for _, marshaler := range []json.Marshaler{tokenizer, charTokenizer} {
+		var bin, js bytes.Buffer
+		if err := m.Save(&bin, marshaler); err != nil {
+			t.Fatalf("Save error: %v", err)
+		}
+		if err := m.SaveJSON(&js, marshaler); err != nil {
+			t.Fatalf("SaveJSON error: %v", err)
+		}
+		if _, err := LoadModel(&bin, nil); err != nil {
+			t.Errorf("LoadModel error: %v", err)
+		}
+		if strings.Contains(js.String(), `"tokenizer"`) {
+			t.Errorf("SaveJSON() saved a nil tokenizer: %s", js.String())
+		}
+		if _, err := LoadModelJSON(&js, nil); err != nil {
+			t.Errorf("LoadModelJSON error: %v", err)
+		}
+	}
+}
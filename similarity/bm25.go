@@ -0,0 +1,224 @@
+package similarity
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rioloc/tfidf-go"
+)
+
+// Scorer is implemented by ranking functions that score an input string against a set of documents.
+// Both CosineSimilarity and BM25 satisfy it, so callers can switch ranking functions freely.
+type Scorer interface {
+	Do(input string, documents []string) ([]float64, error)
+}
+
+// ContextScorer is implemented by scorers whose scoring can be cancelled through a context,
+// like CosineSimilarity and BM25.
+type ContextScorer interface {
+	Scorer
+	DoContext(ctx context.Context, input string, documents []string) ([]float64, error)
+}
+
+var (
+	_ ContextScorer = (*CosineSimilarity)(nil)
+	_ ContextScorer = (*BM25)(nil)
+)
+
+// BM25Variant selects the BM25 ranking formula.
+type BM25Variant int
+
+const (
+	// Okapi is the classic Okapi BM25 ranking function (default).
+	Okapi BM25Variant = iota
+
+	// BM25L shifts the length-normalized term frequency by delta, to avoid
+	// over-penalizing very long documents (Lv and Zhai, 2011).
+	BM25L
+
+	// BM25Plus adds a lower bound delta to the contribution of every matching term,
+	// so that a term occurrence in a long document is never scored below a short one
+	// without it (Lv and Zhai, 2011).
+	BM25Plus
+)
+
+// BM25 scores documents against an input string using the BM25 family of ranking functions.
+// Unlike TF-IDF cosine similarity, BM25 saturates term frequencies (k1) and normalizes them
+// by the document length relative to the average document length (b).
+type BM25 struct {
+	tokenizer tokenizer
+	variant   BM25Variant
+	k1        float64
+	b         float64
+	delta     float64
+	hasDelta  bool
+	workers   int
+}
+
+// BM25Option is a function type that allows for configuring BM25.
+type BM25Option func(*BM25)
+
+// WithK1 is a functional option to set the term frequency saturation parameter. Defaults to 1.2.
+func WithK1(k1 float64) BM25Option {
+	return func(s *BM25) {
+		s.k1 = k1
+	}
+}
+
+// WithB is a functional option to set the document length normalization parameter,
+// between 0 (no normalization) and 1 (full normalization). Defaults to 0.75.
+func WithB(b float64) BM25Option {
+	return func(s *BM25) {
+		s.b = b
+	}
+}
+
+// WithVariant is a functional option to select the BM25 variant. Defaults to Okapi.
+func WithVariant(variant BM25Variant) BM25Option {
+	return func(s *BM25) {
+		s.variant = variant
+	}
+}
+
+// WithDelta is a functional option to set the delta parameter of BM25L and BM25Plus.
+// Defaults to 0.5 for BM25L and 1 for BM25Plus; it is ignored by Okapi.
+func WithDelta(delta float64) BM25Option {
+	return func(s *BM25) {
+		s.delta = delta
+		s.hasDelta = true
+	}
+}
+
+// WithWorkers is a functional option to set the number of goroutines term counting
+// is sharded across, 1 to run sequentially. Defaults to GOMAXPROCS.
+func WithWorkers(n int) BM25Option {
+	return func(s *BM25) {
+		s.workers = n
+	}
+}
+
+// NewBM25 is a constructor function that returns a new BM25 instance.
+// It takes a tokenizer as argument, allowing for dependency injection like NewCosineSimilarity.
+func NewBM25(tokenizer tokenizer, opts ...BM25Option) *BM25 {
+	s := &BM25{
+		tokenizer: tokenizer,
+		variant:   Okapi,
+		k1:        1.2,
+		b:         0.75,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if !s.hasDelta {
+		switch s.variant {
+		case BM25L:
+			s.delta = 0.5
+		case BM25Plus:
+			s.delta = 1
+		}
+	}
+	return s
+}
+
+// Do calculates the BM25 score between an input string and a slice of documents.
+// It returns a slice of float64, where each element is the score of the corresponding document.
+//
+// The IDF of each term is computed from the document frequencies with tfidf.BM25Idf,
+// log((N - df + 0.5) / (df + 0.5) + 1), which is always positive.
+// Every distinct query term contributes once.
+func (s *BM25) Do(input string, documents []string) ([]float64, error) {
+	return s.DoContext(context.Background(), input, documents)
+}
+
+// DoContext is like Do, but returns ctx.Err() as soon as ctx is done. Cancellation is checked
+// between documents while tokenizing, if the tokenizer implements TokenizeContext like
+// *token.Tokenizer, while counting terms, and while scoring.
+func (s *BM25) DoContext(ctx context.Context, input string, documents []string) ([]float64, error) {
+	if len(documents) == 0 {
+		return nil, errors.New("empty documents")
+	}
+
+	// Tokenize the provided documents to create a vocabulary and tokenized representations.
+	vocabulary, tokens, err := tokenize(ctx, s.tokenizer, documents)
+	if err != nil {
+		return nil, err
+	}
+	// Calculate raw term counts and document frequencies for the documents.
+	counter := tfidf.NewTfIdfVectorizer(tfidf.WithWorkers(s.workers))
+	tfMat, err := counter.TfSparseContext(ctx, vocabulary, tokens)
+	if err != nil {
+		return nil, err
+	}
+	dfVec, err := counter.DfContext(ctx, vocabulary, tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate the length of every document and the average length.
+	var avgLen float64
+	lengths := make([]float64, len(tokens))
+	for i, doc := range tokens {
+		lengths[i] = float64(len(doc))
+		avgLen += lengths[i]
+	}
+	avgLen /= float64(len(tokens))
+
+	// Tokenize the input string and map its distinct terms to vocabulary positions.
+	_, queryTokens, err := tokenize(ctx, s.tokenizer, []string{input})
+	if err != nil {
+		return nil, err
+	}
+	queryMat, err := counter.TfSparseContext(ctx, vocabulary, queryTokens)
+	if err != nil {
+		return nil, err
+	}
+	queryTerms := queryMat.Row(0).Indices
+
+	// Calculate the BM25 IDF of the vocabulary terms.
+	idf, err := tfidf.IdfFromDf(dfVec, len(tokens), tfidf.BM25Idf)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]float64, len(tokens))
+	for i := range scores {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		row := tfMat.Row(i)
+		// Both the query terms and the row indices are sorted, so they can be merged.
+		k := 0
+		for _, j := range queryTerms {
+			for k < len(row.Indices) && row.Indices[k] < j {
+				k++
+			}
+			if k == len(row.Indices) {
+				break
+			}
+			if row.Indices[k] == j {
+				scores[i] += idf[j] * s.termScore(row.Values[k], lengths[i], avgLen)
+			}
+		}
+	}
+	return scores, nil
+}
+
+// termScore calculates the length-normalized, saturated contribution of a term
+// occurring tf times in a document of length docLen, according to the variant.
+func (s *BM25) termScore(tf, docLen, avgLen float64) float64 {
+	// Length normalization factor: 1 for a document of average length.
+	norm := 1 - s.b
+	if avgLen > 0 {
+		norm += s.b * docLen / avgLen
+	}
+
+	switch s.variant {
+	case BM25L:
+		ctd := tf / norm
+		return (s.k1 + 1) * (ctd + s.delta) / (s.k1 + ctd + s.delta)
+	case BM25Plus:
+		return tf*(s.k1+1)/(tf+s.k1*norm) + s.delta
+	default:
+		return tf * (s.k1 + 1) / (tf + s.k1*norm)
+	}
+}
@@ -0,0 +1,179 @@
+package similarity
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rioloc/tfidf-go"
+	"github.com/rioloc/tfidf-go/token"
+)
+
+func TestBM25_Do(t *testing.T) {
+	// N = 3, avgdl = (2 + 3 + 1) / 3 = 2
+	documents := []string{"apple banana", "apple apple cherry", "durian"}
+	idf := func(df float64) float64 { return math.Log((3-df+0.5)/(df+0.5) + 1) }
+	k1, b := 1.2, 0.75
+	norm := func(docLen float64) float64 { return 1 - b + b*docLen/2 }
+
+	tests := []struct {
+		name    string
+		input   string
+		opts    []BM25Option
+		want    []float64
+		wantErr bool
+	}{
+		{
+			name:  "Okapi",
+			input: "apple cherry",
+			want: []float64{
+				idf(2) * 1 * (k1 + 1) / (1 + k1*norm(2)),
+				idf(2)*2*(k1+1)/(2+k1*norm(3)) + idf(1)*1*(k1+1)/(1+k1*norm(3)),
+				0,
+			},
+		},
+		{
+			name:  "Sequential",
+			input: "apple cherry",
+			opts:  []BM25Option{WithWorkers(1)},
+			want: []float64{
+				idf(2) * 1 * (k1 + 1) / (1 + k1*norm(2)),
+				idf(2)*2*(k1+1)/(2+k1*norm(3)) + idf(1)*1*(k1+1)/(1+k1*norm(3)),
+				0,
+			},
+		},
+		{
+			name:  "Repeated query terms count once",
+			input: "apple apple",
+			want: []float64{
+				idf(2) * 1 * (k1 + 1) / (1 + k1*norm(2)),
+				idf(2) * 2 * (k1 + 1) / (2 + k1*norm(3)),
+				0,
+			},
+		},
+		{
+			name:  "BM25Plus",
+			input: "banana",
+			opts:  []BM25Option{WithVariant(BM25Plus)},
+			want: []float64{
+				idf(1) * (1*(k1+1)/(1+k1*norm(2)) + 1),
+				0,
+				0,
+			},
+		},
+		{
+			name:  "BM25L",
+			input: "banana",
+			opts:  []BM25Option{WithVariant(BM25L), WithDelta(0.25)},
+			want: []float64{
+				idf(1) * (k1 + 1) * (1/norm(2) + 0.25) / (k1 + 1/norm(2) + 0.25),
+				0,
+				0,
+			},
+		},
+		{
+			name:  "No length normalization",
+			input: "apple",
+			opts:  []BM25Option{WithB(0), WithK1(2)},
+			want: []float64{
+				idf(2) * 1 * 3 / (1 + 2),
+				idf(2) * 2 * 3 / (2 + 2),
+				0,
+			},
+		},
+		{
+			name:  "Unknown terms",
+			input: "grape",
+			want:  []float64{0, 0, 0},
+		},
+	}
+
+	tokenizer := token.NewTokenizer(token.WithNormalizeFunc(strings.ToLower))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewBM25(tokenizer, tt.opts...).Do(tt.input, documents)
+			if err != nil {
+				t.Fatalf("Do() unexpected error: %v", err)
+			}
+			for i := range tt.want {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Errorf("Do() score[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	t.Run("Empty documents list", func(t *testing.T) {
+		if _, err := NewBM25(tokenizer).Do("apple", []string{}); err == nil {
+			t.Errorf("Do() expected error")
+		}
+	})
+}
+
+func TestBM25_Ranking(t *testing.T) {
+	// A Scorer can be swapped without changing the calling code.
+	tokenizer := token.NewTokenizer(token.WithNormalizeFunc(strings.ToLower))
+	var scorer Scorer = NewBM25(tokenizer)
+
+	scores, err := scorer.Do("machine learning", []string{
+		"data mining data analysis",
+		"machine learning deep learning",
+		"big data science and analytics",
+		"data science machine",
+	})
+	if err != nil {
+		t.Fatalf("Do() unexpected error: %v", err)
+	}
+	hits := TopK(scores, 1)
+	if hits[0].Doc != 1 {
+		t.Errorf("best hit = %d, want 1", hits[0].Doc)
+	}
+}
+
+func TestContextScorer_DoContext(t *testing.T) {
+	var normalized atomic.Int64
+	tokenizer := token.NewTokenizer(token.WithNormalizeFunc(func(s string) string {
+		normalized.Add(1)
+		return strings.ToLower(s)
+	}))
+	input := "data science machine learning"
+	documents := []string{"data mining data analysis", "machine learning deep learning", "big data science and analytics"}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name   string
+		scorer ContextScorer
+	}{
+		{name: "cosine", scorer: NewCosineSimilarity(tokenizer, tfidf.NewTfIdfVectorizer())},
+		{name: "cosine dense", scorer: NewCosineSimilarity(tokenizer, denseVectorizer{tfidf.NewTfIdfVectorizer()})},
+		{name: "bm25", scorer: NewBM25(tokenizer)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := tt.scorer.Do(input, documents)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := tt.scorer.DoContext(context.Background(), input, documents)
+			if err != nil {
+				t.Fatalf("DoContext() error = %v", err)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("DoContext() = %v, want %v", got, want)
+			}
+			before := normalized.Load()
+			if _, err := tt.scorer.DoContext(canceled, input, documents); !errors.Is(err, context.Canceled) {
+				t.Errorf("DoContext() error = %v, want %v", err, context.Canceled)
+			}
+			if normalized.Load() != before {
+				t.Error("DoContext() tokenized documents after cancellation")
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package similarity
 
 import (
+	"context"
 	"math"
 
 	"github.com/rioloc/tfidf-go"
@@ -12,12 +13,91 @@ type tokenizer interface {
 	Tokenize(documents []string) ([]string, [][]string, error)
 }
 
+// contextTokenizer is implemented by tokenizers that can check a context between documents,
+// like token.Tokenizer and token.CharNGramTokenizer.
+type contextTokenizer interface {
+	TokenizeContext(ctx context.Context, documents []string) ([]string, [][]string, error)
+}
+
+// tokenize tokenizes the documents, checking ctx between documents if the tokenizer supports it,
+// and once tokenization is done otherwise.
+func tokenize(ctx context.Context, t tokenizer, documents []string) ([]string, [][]string, error) {
+	if ct, ok := t.(contextTokenizer); ok {
+		return ct.TokenizeContext(ctx, documents)
+	}
+	vocabulary, tokens, err := t.Tokenize(documents)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	return vocabulary, tokens, nil
+}
+
 // vectorizer is an interface that defines the TfIdf method.
 // This allows for different TF-IDF vectorization strategies to be used.
 type vectorizer interface {
 	TfIdf(tfVec [][]float64, idfVec []float64) (tfIdfMat [][]float64, err error)
 }
 
+// sparseVectorizer is implemented by vectorizers that can also compute TF-IDF in sparse (CSR) format.
+// When available, it is preferred to avoid allocating dense documents × vocabulary matrices.
+type sparseVectorizer interface {
+	TfIdfSparse(tf *tfidf.SparseMatrix, idfVec []float64) (*tfidf.SparseMatrix, error)
+}
+
+// contextVectorizer is implemented by vectorizers that can check a context between documents,
+// like tfidf.TfIdfVectorizer.
+type contextVectorizer interface {
+	TfIdfContext(ctx context.Context, tfVec [][]float64, idfVec []float64) ([][]float64, error)
+}
+
+// contextSparseVectorizer is the sparse counterpart of contextVectorizer.
+type contextSparseVectorizer interface {
+	TfIdfSparseContext(ctx context.Context, tf *tfidf.SparseMatrix, idfVec []float64) (*tfidf.SparseMatrix, error)
+}
+
+// tfIdf weights the term counts, checking ctx between documents if the vectorizer supports it,
+// and once weighting is done otherwise.
+func tfIdf(ctx context.Context, v vectorizer, tfVec [][]float64, idfVec []float64) ([][]float64, error) {
+	if cv, ok := v.(contextVectorizer); ok {
+		return cv.TfIdfContext(ctx, tfVec, idfVec)
+	}
+	tfIdfMat, err := v.TfIdf(tfVec, idfVec)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return tfIdfMat, nil
+}
+
+// tfIdfSparse is the sparse counterpart of tfIdf.
+func tfIdfSparse(ctx context.Context, v sparseVectorizer, tf *tfidf.SparseMatrix, idfVec []float64) (*tfidf.SparseMatrix, error) {
+	if cv, ok := v.(contextSparseVectorizer); ok {
+		return cv.TfIdfSparseContext(ctx, tf, idfVec)
+	}
+	tfIdfMat, err := v.TfIdfSparse(tf, idfVec)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return tfIdfMat, nil
+}
+
+// countingVectorizer is implemented by vectorizers that also count terms, like tfidf.TfIdfVectorizer,
+// so that their options, e.g. the number of workers, apply to counting too.
+// Counting checks ctx between documents.
+type countingVectorizer interface {
+	TfContext(ctx context.Context, vocabulary []string, tokens [][]string) ([][]float64, error)
+	TfSparseContext(ctx context.Context, vocabulary []string, tokens [][]string) (*tfidf.SparseMatrix, error)
+	IdfContext(ctx context.Context, vocabulary []string, tokens [][]string, smoothing bool) ([]float64, error)
+}
+
 // CosineSimilarity struct holds the tokenizer and vectorizer implementations.
 // It is designed to calculate cosine similarity between an input string and a set of documents.
 type CosineSimilarity struct {
@@ -38,31 +118,56 @@ func NewCosineSimilarity(tokenizer tokenizer, vectorizer vectorizer) *CosineSimi
 // It returns a slice of float64, where each element is the cosine similarity score
 // between the input string and the corresponding document.
 func (c *CosineSimilarity) Do(input string, documents []string) ([]float64, error) {
+	return c.DoContext(context.Background(), input, documents)
+}
+
+// DoContext is like Do, but returns ctx.Err() as soon as ctx is done. Cancellation is checked
+// between documents while tokenizing, if the tokenizer implements TokenizeContext like
+// *token.Tokenizer, while counting terms, while weighting them, if the vectorizer implements
+// TfIdfContext like *tfidf.TfIdfVectorizer, and while scoring. Other tokenizers and vectorizers
+// run to completion, and ctx is checked once they return.
+func (c *CosineSimilarity) DoContext(ctx context.Context, input string, documents []string) ([]float64, error) {
 	// Tokenize the provided documents to create a vocabulary and tokenized representations.
-	vocabulary, tokens, err := c.tokenizer.Tokenize(documents)
+	vocabulary, tokens, err := tokenize(ctx, c.tokenizer, documents)
 	if err != nil {
 		return nil, err
 	}
-	// Calculate Term Frequency (TF) for the documents.
-	tfVec := tfidf.Tf(vocabulary, tokens)
 	// Calculate Inverse Document Frequency (IDF) for the vocabulary.
-	idfVec := tfidf.Idf(vocabulary, tokens, true)
+	counter := c.counter()
+	idfVec, err := counter.IdfContext(ctx, vocabulary, tokens, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prefer the sparse pipeline when the vectorizer supports it.
+	if sv, ok := c.vectorizer.(sparseVectorizer); ok {
+		return c.doSparse(ctx, sv, counter, input, vocabulary, tokens, idfVec)
+	}
+
+	// Calculate Term Frequency (TF) for the documents.
+	tfVec, err := counter.TfContext(ctx, vocabulary, tokens)
+	if err != nil {
+		return nil, err
+	}
 
 	// Calculate TF-IDF vectors for the documents.
-	tfIdfVec, err := c.vectorizer.TfIdf(tfVec, idfVec)
+	tfIdfVec, err := tfIdf(ctx, c.vectorizer, tfVec, idfVec)
 	if err != nil {
 		return nil, err
 	}
 
 	// Tokenize the input string to generate its tokens.
-	_, queryTokens, err := c.tokenizer.Tokenize([]string{input})
+	_, queryTokens, err := tokenize(ctx, c.tokenizer, []string{input})
 	if err != nil {
 		return nil, err
 	}
 	// Calculate Term Frequency (TF) for the input string using the same vocabulary.
-	tf := tfidf.Tf(vocabulary, queryTokens)
+	tf, err := counter.TfContext(ctx, vocabulary, queryTokens)
+	if err != nil {
+		return nil, err
+	}
 	// Calculate TF-IDF vector for the input string.
-	tfIdf, err := c.vectorizer.TfIdf(tf, idfVec)
+	query, err := tfIdf(ctx, c.vectorizer, tf, idfVec)
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +176,48 @@ func (c *CosineSimilarity) Do(input string, documents []string) ([]float64, erro
 	scores := make([]float64, len(documents))
 	// Iterate through each document's TF-IDF vector and calculate its cosine similarity with the input string's TF-IDF vector.
 	for i, vec := range tfIdfVec {
-		scores[i] = cosineSimilarity(tfIdf[0], vec)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		scores[i] = cosineSimilarity(query[0], vec)
+	}
+	return scores, nil
+}
+
+// doSparse is the sparse counterpart of Do: TF and TF-IDF vectors are kept in CSR format
+// and the cosine similarity is computed directly on the sparse rows.
+func (c *CosineSimilarity) doSparse(ctx context.Context, sv sparseVectorizer, counter countingVectorizer, input string, vocabulary []string, tokens [][]string, idfVec []float64) ([]float64, error) {
+	// Calculate TF-IDF vectors for the documents.
+	tf, err := counter.TfSparseContext(ctx, vocabulary, tokens)
+	if err != nil {
+		return nil, err
+	}
+	tfIdfMat, err := tfIdfSparse(ctx, sv, tf, idfVec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tokenize the input string and calculate its TF-IDF vector using the same vocabulary.
+	_, queryTokens, err := tokenize(ctx, c.tokenizer, []string{input})
+	if err != nil {
+		return nil, err
+	}
+	queryTf, err := counter.TfSparseContext(ctx, vocabulary, queryTokens)
+	if err != nil {
+		return nil, err
+	}
+	queryMat, err := tfIdfSparse(ctx, sv, queryTf, idfVec)
+	if err != nil {
+		return nil, err
+	}
+
+	query := queryMat.Row(0)
+	scores := make([]float64, tfIdfMat.Rows())
+	for i := range scores {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		scores[i] = cosineSimilaritySparse(query, tfIdfMat.Row(i))
 	}
 	return scores, nil
 }
@@ -93,3 +239,24 @@ func cosineSimilarity(vec1, vec2 []float64) float64 {
 	// Calculate and return the cosine similarity.
 	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
 }
+
+// cosineSimilaritySparse calculates the cosine similarity between two sparse vectors.
+// Only the stored (non-zero) values are visited.
+func cosineSimilaritySparse(vec1, vec2 tfidf.SparseVector) float64 {
+	normA := vec1.Dot(vec1)
+	normB := vec2.Dot(vec2)
+	// If either vector has a zero magnitude, return 0.0 to avoid division by zero.
+	if normA == 0 || normB == 0 {
+		return 0.0
+	}
+	return vec1.Dot(vec2) / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// counter returns the vectorizer counting terms: the vectorizer itself if it can,
+// otherwise a default tfidf.TfIdfVectorizer, which uses GOMAXPROCS workers.
+func (c *CosineSimilarity) counter() countingVectorizer {
+	if cv, ok := c.vectorizer.(countingVectorizer); ok {
+		return cv
+	}
+	return tfidf.NewTfIdfVectorizer()
+}
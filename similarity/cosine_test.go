@@ -126,6 +126,51 @@ func TestCosineSimilarity_Do(t *testing.T) {
 	}
 }
 
+// denseVectorizer hides the sparse methods of the library vectorizer,
+// forcing CosineSimilarity to go through the dense pipeline.
+type denseVectorizer struct {
+	v *tfidf.TfIdfVectorizer
+}
+
+func (d denseVectorizer) TfIdf(tfVec [][]float64, idfVec []float64) ([][]float64, error) {
+	return d.v.TfIdf(tfVec, idfVec)
+}
+
+func TestCosineSimilarity_Do_DenseVectorizer(t *testing.T) {
+	tokenizer := token.NewTokenizer(token.WithNormalizeFunc(strings.ToLower))
+	input := "data science machine learning"
+	documents := []string{
+		"data mining data analysis",
+		"machine learning deep learning",
+		"big data science and analytics",
+	}
+
+	want, err := NewCosineSimilarity(tokenizer, tfidf.NewTfIdfVectorizer()).Do(input, documents)
+	if err != nil {
+		t.Fatalf("Do() sparse error: %v", err)
+	}
+	got, err := NewCosineSimilarity(tokenizer, denseVectorizer{tfidf.NewTfIdfVectorizer()}).Do(input, documents)
+	if err != nil {
+		t.Fatalf("Do() dense error: %v", err)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("score[%d]: dense %v, sparse %v", i, got[i], want[i])
+		}
+	}
+
+	// The workers of the vectorizer also count the terms, with the same output
+	sequential, err := NewCosineSimilarity(tokenizer, tfidf.NewTfIdfVectorizer(tfidf.WithWorkers(1))).Do(input, documents)
+	if err != nil {
+		t.Fatalf("Do() sequential error: %v", err)
+	}
+	for i := range want {
+		if sequential[i] != want[i] {
+			t.Errorf("score[%d]: sequential %v, parallel %v", i, sequential[i], want[i])
+		}
+	}
+}
+
 func Test_cosineSimilarity(t *testing.T) {
 	tests := []struct {
 		name string
@@ -199,6 +244,31 @@ func Test_cosineSimilarity(t *testing.T) {
 	}
 }
 
+func Test_cosineSimilaritySparse(t *testing.T) {
+	tests := []struct {
+		name string
+		vec1 []float64
+		vec2 []float64
+	}{
+		{name: "Perfect match", vec1: []float64{1, 1, 1}, vec2: []float64{1, 1, 1}},
+		{name: "Orthogonal", vec1: []float64{1, 0, 0}, vec2: []float64{0, 1, 0}},
+		{name: "Partial commonality", vec1: []float64{1, 1, 0}, vec2: []float64{1, 0, 1}},
+		{name: "One zero vector", vec1: []float64{0, 0, 0}, vec2: []float64{1, 1, 1}},
+		{name: "Complex vectors", vec1: []float64{1, 0, 3}, vec2: []float64{4, 5, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mat := tfidf.SparseFromDense([][]float64{tt.vec1, tt.vec2})
+			got := cosineSimilaritySparse(mat.Row(0), mat.Row(1))
+			want := cosineSimilarity(tt.vec1, tt.vec2)
+			if math.Abs(got-want) > 1e-9 {
+				t.Errorf("cosineSimilaritySparse() got = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
 // sampleWords is a pool of words to generate varied documents
 var sampleWords = []string{
 	"data", "science", "machine", "learning", "deep", "neural", "networks",
@@ -0,0 +1,230 @@
+package similarity
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/rioloc/tfidf-go"
+)
+
+// model is an interface that defines a fitted TF-IDF model producing sparse vectors.
+// It is satisfied by *tfidf.Model.
+type model interface {
+	FitTransformSparse(tokens [][]string) (*tfidf.SparseMatrix, error)
+	TransformSparse(tokens [][]string) (*tfidf.SparseMatrix, error)
+}
+
+// queryModel is implemented by models weighting queries differently from documents,
+// like *tfidf.Model configured with tfidf.WithSmart.
+type queryModel interface {
+	TransformQuerySparse(tokens [][]string) (*tfidf.SparseMatrix, error)
+}
+
+// contextModel is implemented by models whose fitting and vectorization can be cancelled,
+// like *tfidf.Model. Other models are only checked for cancellation between steps.
+type contextModel interface {
+	FitTransformSparseContext(ctx context.Context, tokens [][]string) (*tfidf.SparseMatrix, error)
+	TransformSparseContext(ctx context.Context, tokens [][]string) (*tfidf.SparseMatrix, error)
+}
+
+// contextQueryModel is the cancellable counterpart of queryModel.
+type contextQueryModel interface {
+	TransformQuerySparseContext(ctx context.Context, tokens [][]string) (*tfidf.SparseMatrix, error)
+}
+
+// Index holds a corpus vectorized once, so that repeated queries only pay for
+// tokenizing and scoring the query instead of refitting the whole corpus as
+// CosineSimilarity.Do does.
+//
+// Document vectors are stored in an InvertedIndex, so only documents sharing
+// at least one term with the query are scored.
+type Index struct {
+	tokenizer tokenizer
+	model     model
+	inverted  *InvertedIndex // Posting lists of the indexed document vectors
+	norms     []float64      // Euclidean norm of each document vector
+	ids       []string       // Optional caller-supplied document identifiers
+	strategy  Strategy       // Accumulation strategy over the posting lists
+	inner     bool           // Whether scores are inner products instead of cosine similarities
+}
+
+// IndexOption is a function type that allows for configuring the Index.
+type IndexOption func(*Index)
+
+// WithDocumentIDs is a functional option to identify documents in search results
+// with caller-supplied IDs instead of their position. ids must be aligned with the documents.
+func WithDocumentIDs(ids []string) IndexOption {
+	return func(x *Index) {
+		x.ids = ids
+	}
+}
+
+// WithStrategy is a functional option to set how query scores are accumulated
+// over the posting lists. Defaults to TermAtATime.
+func WithStrategy(strategy Strategy) IndexOption {
+	return func(x *Index) {
+		x.strategy = strategy
+	}
+}
+
+// WithInnerProduct is a functional option to score documents with the inner product of the query
+// and document vectors, instead of their cosine similarity. It is the retrieval function of
+// SMART weighting schemes, where documents and queries are normalized by their own schemes,
+// e.g. with pivoted normalization, that cosine similarity would override.
+func WithInnerProduct() IndexOption {
+	return func(x *Index) {
+		x.inner = true
+	}
+}
+
+// NewIndex tokenizes the documents, fits the model on them and stores the resulting
+// document vectors. The model is fitted in place and must not be refitted afterwards.
+//
+// Example:
+//
+//	idx, _ := NewIndex(token.NewTokenizer(), tfidf.NewModel(), documents)
+//	scores, _ := idx.Query("some query")
+func NewIndex(tokenizer tokenizer, model model, documents []string, opts ...IndexOption) (*Index, error) {
+	return NewIndexContext(context.Background(), tokenizer, model, documents, opts...)
+}
+
+// NewIndexContext is like NewIndex, but returns ctx.Err() as soon as ctx is done.
+// With a tokenizer implementing TokenizeContext, like *token.Tokenizer, cancellation is checked
+// between documents while tokenizing, and with a model implementing FitTransformSparseContext,
+// like *tfidf.Model, between documents while fitting; otherwise it is checked between the steps.
+func NewIndexContext(ctx context.Context, tokenizer tokenizer, model model, documents []string, opts ...IndexOption) (*Index, error) {
+	x := &Index{
+		tokenizer: tokenizer,
+		model:     model,
+	}
+	// Apply all provided options to the index.
+	for _, opt := range opts {
+		opt(x)
+	}
+	if x.ids != nil && len(x.ids) != len(documents) {
+		return nil, errors.New("document IDs and documents lengths don't match")
+	}
+
+	// Tokenize the provided documents and fit the model on them.
+	_, tokens, err := tokenize(ctx, tokenizer, documents)
+	if err != nil {
+		return nil, err
+	}
+	fitTransform := model.FitTransformSparse
+	if cm, ok := model.(contextModel); ok {
+		fitTransform = func(tokens [][]string) (*tfidf.SparseMatrix, error) {
+			return cm.FitTransformSparseContext(ctx, tokens)
+		}
+	}
+	docs, err := fitTransform(tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pre-compute the document norms, which are constant across queries.
+	norms := make([]float64, docs.Rows())
+	for i := range norms {
+		row := docs.Row(i)
+		norms[i] = math.Sqrt(row.Dot(row))
+	}
+
+	x.inverted = NewInvertedIndex(docs)
+	x.norms = norms
+	return x, nil
+}
+
+// Len returns the number of indexed documents.
+func (x *Index) Len() int {
+	return x.inverted.Len()
+}
+
+// Query calculates the cosine similarity between the input string and every indexed document,
+// or their inner product if the index was created with WithInnerProduct.
+// It returns a slice of float64 aligned with the documents passed to NewIndex.
+func (x *Index) Query(input string) ([]float64, error) {
+	return x.QueryContext(context.Background(), input)
+}
+
+// QueryContext is like Query, but checks ctx between documents and returns ctx.Err() as soon as ctx is done.
+func (x *Index) QueryContext(ctx context.Context, input string) ([]float64, error) {
+	scores := make([]float64, x.Len())
+	err := x.score(ctx, input, func(doc int, score float64) {
+		scores[doc] = score
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
+
+// score vectorizes the input string and calls fn with the cosine similarity of every
+// document sharing at least one term with it, in increasing document order.
+func (x *Index) score(ctx context.Context, input string, fn func(doc int, score float64)) error {
+	// Tokenize the input string and vectorize it against the fitted vocabulary.
+	_, queryTokens, err := tokenize(ctx, x.tokenizer, []string{input})
+	if err != nil {
+		return err
+	}
+	queryMat, err := x.transform(ctx, queryTokens)
+	if err != nil {
+		return err
+	}
+
+	query := queryMat.Row(0)
+	queryNorm := math.Sqrt(query.Dot(query))
+	// A query without any known term is orthogonal to every document.
+	if queryNorm == 0 {
+		return nil
+	}
+	return x.inverted.accumulate(ctx, query, x.strategy, func(doc int, dot float64) {
+		// Skip documents whose vector is all zeros.
+		if x.norms[doc] == 0 {
+			return
+		}
+		if x.inner {
+			fn(doc, dot)
+			return
+		}
+		fn(doc, dot/(queryNorm*x.norms[doc]))
+	})
+}
+
+// transform vectorizes the query tokens with the query weighting of the model, if it has one,
+// passing ctx to the model if it accepts one.
+func (x *Index) transform(ctx context.Context, tokens [][]string) (*tfidf.SparseMatrix, error) {
+	if qm, ok := x.model.(contextQueryModel); ok {
+		return qm.TransformQuerySparseContext(ctx, tokens)
+	}
+	if qm, ok := x.model.(queryModel); ok {
+		return qm.TransformQuerySparse(tokens)
+	}
+	if cm, ok := x.model.(contextModel); ok {
+		return cm.TransformSparseContext(ctx, tokens)
+	}
+	return x.model.TransformSparse(tokens)
+}
+
+// Search scores the input string against the indexed documents and returns the k best hits
+// in decreasing score order, identified by the IDs supplied with WithDocumentIDs if any.
+// Ties are broken by document position. If k <= 0, all matching documents are returned ranked.
+// Documents sharing no term with the query are never returned.
+//
+// Example:
+//
+//	hits, _ := idx.Search("some query", 10, WithMinScore(0.1))
+//	for _, hit := range hits {
+//		fmt.Println(hit.ID, hit.Score)
+//	}
+func (x *Index) Search(input string, k int, opts ...SearchOption) ([]Hit, error) {
+	return x.SearchContext(context.Background(), input, k, opts...)
+}
+
+// SearchContext is like Search, but checks ctx between documents and returns ctx.Err() as soon as ctx is done.
+func (x *Index) SearchContext(ctx context.Context, input string, k int, opts ...SearchOption) ([]Hit, error) {
+	c := newCollector(k, opts...)
+	if err := x.score(ctx, input, c.push); err != nil {
+		return nil, err
+	}
+	return c.hits(x.ids), nil
+}
@@ -0,0 +1,158 @@
+package similarity
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rioloc/tfidf-go"
+	"github.com/rioloc/tfidf-go/token"
+)
+
+func TestIndex_Query(t *testing.T) {
+	documents := []string{
+		"data mining data analysis",
+		"machine learning deep learning",
+		"big data science and analytics",
+		"data science machine",
+	}
+	queries := []string{
+		"data science machine learning",
+		"deep analysis",
+		"unknown words only",
+		"",
+	}
+
+	tokenizer := token.NewTokenizer(token.WithNormalizeFunc(strings.ToLower))
+	idx, err := NewIndex(tokenizer, tfidf.NewModel(), documents)
+	if err != nil {
+		t.Fatalf("NewIndex() error: %v", err)
+	}
+	if idx.Len() != len(documents) {
+		t.Fatalf("Len() = %d, want %d", idx.Len(), len(documents))
+	}
+
+	// The index must produce the same scores as refitting on every query.
+	cs := NewCosineSimilarity(tokenizer, tfidf.NewTfIdfVectorizer())
+	for _, query := range queries {
+		t.Run(query, func(t *testing.T) {
+			got, err := idx.Query(query)
+			if err != nil {
+				t.Fatalf("Query() error: %v", err)
+			}
+			want, err := cs.Do(query, documents)
+			if err != nil {
+				t.Fatalf("Do() error: %v", err)
+			}
+			for i := range want {
+				if math.Abs(got[i]-want[i]) > 1e-9 {
+					t.Errorf("score[%d] = %v, want %v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_Query_Smart(t *testing.T) {
+	documents := []string{
+		"data mining data analysis",
+		"machine learning deep learning",
+		"big data science and analytics",
+	}
+	query := "data science science"
+
+	tokenizer := token.NewTokenizer()
+	doc, q, err := tfidf.ParseSmart("lnc.ltc")
+	if err != nil {
+		t.Fatalf("ParseSmart() error: %v", err)
+	}
+	model := tfidf.NewModel(tfidf.WithSmart(doc, q))
+	idx, err := NewIndex(tokenizer, model, documents, WithInnerProduct())
+	if err != nil {
+		t.Fatalf("NewIndex() error: %v", err)
+	}
+	got, err := idx.Query(query)
+	if err != nil {
+		t.Fatalf("Query() error: %v", err)
+	}
+
+	// Scores are the inner products of lnc document vectors and the ltc query vector.
+	_, docTokens, _ := tokenizer.Tokenize(documents)
+	_, queryTokens, _ := tokenizer.Tokenize([]string{query})
+	docs, _ := model.Transform(docTokens)
+	queryVec, _ := model.TransformQuery(queryTokens)
+	for i := range docs {
+		var want float64
+		for j := range docs[i] {
+			want += docs[i][j] * queryVec[0][j]
+		}
+		if math.Abs(got[i]-want) > 1e-9 {
+			t.Errorf("score[%d] = %v, want %v", i, got[i], want)
+		}
+	}
+	if got[1] != 0 || got[0] <= 0 || got[2] <= got[0] {
+		t.Errorf("unexpected ranking: %v", got)
+	}
+}
+
+func TestNewIndex_Errors(t *testing.T) {
+	tokenizer := token.NewTokenizer()
+	if _, err := NewIndex(tokenizer, tfidf.NewModel(), []string{}); err == nil {
+		t.Errorf("NewIndex() expected error on empty documents")
+	}
+}
+
+func BenchmarkIndex_Query_Large(b *testing.B) {
+	input := generateDoc(50)
+
+	documents := make([]string, 1000)
+	for i := range documents {
+		documents[i] = generateDoc(50)
+	}
+
+	tokenizer := token.NewTokenizer(token.WithNormalizeFunc(strings.ToLower))
+	idx, err := NewIndex(tokenizer, tfidf.NewModel(), documents)
+	if err != nil {
+		b.Fatalf("NewIndex() error: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = idx.Query(input)
+	}
+}
+
+func TestIndex_Context(t *testing.T) {
+	documents := []string{"data mining data analysis", "machine learning deep learning", "data science and machine learning"}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewIndexContext(canceled, token.NewTokenizer(), tfidf.NewModel(), documents); !errors.Is(err, context.Canceled) {
+		t.Errorf("NewIndexContext() error = %v, want %v", err, context.Canceled)
+	}
+
+	idx, err := NewIndexContext(context.Background(), token.NewTokenizer(), tfidf.NewModel(), documents)
+	if err != nil {
+		t.Fatalf("NewIndexContext() error = %v", err)
+	}
+	for _, strategy := range []Strategy{TermAtATime, DocumentAtATime} {
+		idx.strategy = strategy
+		want, _ := idx.Query("machine learning data")
+		got, err := idx.QueryContext(context.Background(), "machine learning data")
+		if err != nil {
+			t.Fatalf("QueryContext() error = %v", err)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("QueryContext() = %v, want %v", got, want)
+		}
+		if _, err := idx.QueryContext(canceled, "machine learning data"); !errors.Is(err, context.Canceled) {
+			t.Errorf("QueryContext() error = %v, want %v", err, context.Canceled)
+		}
+		if _, err := idx.SearchContext(canceled, "machine learning data", 2); !errors.Is(err, context.Canceled) {
+			t.Errorf("SearchContext() error = %v, want %v", err, context.Canceled)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package similarity
+
+import (
+	"context"
+	"slices"
+
+	"github.com/rioloc/tfidf-go"
+)
+
+// Strategy selects how query scores are accumulated over the posting lists of an InvertedIndex.
+type Strategy int
+
+const (
+	// TermAtATime walks the posting list of one query term at a time, adding its contribution
+	// to a per-document accumulator. It is simple and fast for short queries (default).
+	TermAtATime Strategy = iota
+
+	// DocumentAtATime walks the posting lists of all query terms in parallel, in document order,
+	// and fully scores one document before moving to the next. It needs no accumulator and
+	// emits documents in increasing order.
+	DocumentAtATime
+)
+
+// Posting is an entry of a posting list: the weight of a term in a document.
+type Posting struct {
+	Doc    int     // Position of the document in the indexed matrix
+	Weight float64 // Weight of the term in the document vector
+}
+
+// InvertedIndex maps each term (column of a TF-IDF matrix) to the posting list of the
+// documents containing it, so that only documents sharing at least one term with a query
+// are scored. The cost of a query depends on the length of its terms' posting lists
+// rather than on the size of the corpus.
+type InvertedIndex struct {
+	postings [][]Posting // postings[j] lists the documents containing term j, sorted by Doc
+	numDocs  int
+}
+
+// NewInvertedIndex builds an inverted index from a sparse document-term matrix.
+func NewInvertedIndex(mat *tfidf.SparseMatrix) *InvertedIndex {
+	// Count postings per term first, to allocate each list exactly once.
+	counts := make([]int, mat.Cols)
+	for _, j := range mat.Indices {
+		counts[j]++
+	}
+	postings := make([][]Posting, mat.Cols)
+	for j, count := range counts {
+		if count > 0 {
+			postings[j] = make([]Posting, 0, count)
+		}
+	}
+
+	// Rows are visited in order, so every posting list is naturally sorted by document.
+	for i := 0; i < mat.Rows(); i++ {
+		row := mat.Row(i)
+		for k, j := range row.Indices {
+			postings[j] = append(postings[j], Posting{Doc: i, Weight: row.Values[k]})
+		}
+	}
+
+	return &InvertedIndex{
+		postings: postings,
+		numDocs:  mat.Rows(),
+	}
+}
+
+// Len returns the number of indexed documents.
+func (ii *InvertedIndex) Len() int {
+	return ii.numDocs
+}
+
+// Postings returns the posting list of a term, sorted by document.
+// The returned slice must not be modified.
+func (ii *InvertedIndex) Postings(term int) []Posting {
+	if term < 0 || term >= len(ii.postings) {
+		return nil
+	}
+	return ii.postings[term]
+}
+
+// Accumulate computes the dot product between the query vector and every document sharing
+// at least one term with it, and calls fn once per such document, in increasing document order.
+// Documents sharing no term with the query are never visited.
+func (ii *InvertedIndex) Accumulate(query tfidf.SparseVector, strategy Strategy, fn func(doc int, dot float64)) {
+	// The background context is never done, so no error can occur
+	_ = ii.accumulate(context.Background(), query, strategy, fn)
+}
+
+// accumulate is like Accumulate, but checks ctx between query terms and between documents,
+// and returns ctx.Err() as soon as ctx is done.
+func (ii *InvertedIndex) accumulate(ctx context.Context, query tfidf.SparseVector, strategy Strategy, fn func(doc int, dot float64)) error {
+	switch strategy {
+	case DocumentAtATime:
+		return ii.documentAtATime(ctx, query, fn)
+	default:
+		return ii.termAtATime(ctx, query, fn)
+	}
+}
+
+// termAtATime adds the contribution of each query term to a sparse accumulator,
+// then emits the accumulated documents in order.
+func (ii *InvertedIndex) termAtATime(ctx context.Context, query tfidf.SparseVector, fn func(doc int, dot float64)) error {
+	acc := make(map[int]float64)
+	for k, j := range query.Indices {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		weight := query.Values[k]
+		for _, p := range ii.Postings(j) {
+			acc[p.Doc] += weight * p.Weight
+		}
+	}
+
+	docs := make([]int, 0, len(acc))
+	for doc := range acc {
+		docs = append(docs, doc)
+	}
+	slices.Sort(docs)
+	for _, doc := range docs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		fn(doc, acc[doc])
+	}
+	return nil
+}
+
+// documentAtATime keeps a cursor on each query term's posting list and repeatedly scores
+// the smallest document under any cursor, advancing all cursors positioned on it.
+func (ii *InvertedIndex) documentAtATime(ctx context.Context, query tfidf.SparseVector, fn func(doc int, dot float64)) error {
+	lists := make([][]Posting, len(query.Indices))
+	for k, j := range query.Indices {
+		lists[k] = ii.Postings(j)
+	}
+	cursors := make([]int, len(lists))
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Find the smallest document among the current cursor positions.
+		doc := -1
+		for k, list := range lists {
+			if cursors[k] < len(list) && (doc == -1 || list[cursors[k]].Doc < doc) {
+				doc = list[cursors[k]].Doc
+			}
+		}
+		if doc == -1 {
+			return nil // All posting lists are exhausted.
+		}
+
+		// Score the document and move past it on every list.
+		var dot float64
+		for k, list := range lists {
+			if cursors[k] < len(list) && list[cursors[k]].Doc == doc {
+				dot += query.Values[k] * list[cursors[k]].Weight
+				cursors[k]++
+			}
+		}
+		fn(doc, dot)
+	}
+}
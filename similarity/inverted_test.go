@@ -0,0 +1,88 @@
+package similarity
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rioloc/tfidf-go"
+	"github.com/rioloc/tfidf-go/token"
+)
+
+func TestInvertedIndex_Accumulate(t *testing.T) {
+	docs := tfidf.SparseFromDense([][]float64{
+		{1, 0, 2, 0},
+		{0, 3, 0, 0},
+		{4, 0, 0, 5},
+		{0, 0, 0, 0},
+		{0, 6, 7, 0},
+	})
+	query := tfidf.SparseFromDense([][]float64{{1, 0, 2, 0}}).Row(0)
+
+	ii := NewInvertedIndex(docs)
+	if ii.Len() != 5 {
+		t.Fatalf("Len() = %d, want 5", ii.Len())
+	}
+	if got := ii.Postings(0); !slices.Equal(got, []Posting{{Doc: 0, Weight: 1}, {Doc: 2, Weight: 4}}) {
+		t.Errorf("Postings(0) = %v", got)
+	}
+	if got := ii.Postings(10); got != nil {
+		t.Errorf("Postings(10) = %v, want nil", got)
+	}
+
+	// Only documents 0, 2 and 4 share a term with the query.
+	wantDocs := []int{0, 2, 4}
+	wantDots := []float64{1*1 + 2*2, 1 * 4, 2 * 7}
+
+	for _, strategy := range []Strategy{TermAtATime, DocumentAtATime} {
+		var gotDocs []int
+		var gotDots []float64
+		ii.Accumulate(query, strategy, func(doc int, dot float64) {
+			gotDocs = append(gotDocs, doc)
+			gotDots = append(gotDots, dot)
+		})
+		if !slices.Equal(gotDocs, wantDocs) {
+			t.Errorf("strategy %d: got docs %v, want %v", strategy, gotDocs, wantDocs)
+		}
+		for i := range wantDots {
+			if math.Abs(gotDots[i]-wantDots[i]) > 1e-9 {
+				t.Errorf("strategy %d: doc %d dot = %v, want %v", strategy, gotDocs[i], gotDots[i], wantDots[i])
+			}
+		}
+	}
+}
+
+func TestIndex_Strategies(t *testing.T) {
+	input := generateDoc(20)
+	documents := make([]string, 100)
+	for i := range documents {
+		documents[i] = generateDoc(20)
+	}
+
+	tokenizer := token.NewTokenizer(token.WithNormalizeFunc(strings.ToLower))
+	taat, err := NewIndex(tokenizer, tfidf.NewModel(), documents, WithStrategy(TermAtATime))
+	if err != nil {
+		t.Fatalf("NewIndex() error: %v", err)
+	}
+	daat, err := NewIndex(tokenizer, tfidf.NewModel(), documents, WithStrategy(DocumentAtATime))
+	if err != nil {
+		t.Fatalf("NewIndex() error: %v", err)
+	}
+
+	want, err := NewCosineSimilarity(tokenizer, tfidf.NewTfIdfVectorizer()).Do(input, documents)
+	if err != nil {
+		t.Fatalf("Do() error: %v", err)
+	}
+	for _, idx := range []*Index{taat, daat} {
+		got, err := idx.Query(input)
+		if err != nil {
+			t.Fatalf("Query() error: %v", err)
+		}
+		for i := range want {
+			if math.Abs(got[i]-want[i]) > 1e-9 {
+				t.Errorf("strategy %d: score[%d] = %v, want %v", idx.strategy, i, got[i], want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package similarity
+
+import (
+	"container/heap"
+	"slices"
+	"strconv"
+)
+
+// Hit is a single ranked search result.
+type Hit struct {
+	Doc   int     // Position of the document in the slice it was indexed from
+	ID    string  // Document identifier, the position itself unless IDs were supplied
+	Score float64 // Similarity score between the query and the document
+}
+
+// SearchOption is a function type that allows for configuring a search.
+type SearchOption func(*searchConfig)
+
+// searchConfig holds the settings of a single search.
+type searchConfig struct {
+	minScore    float64
+	hasMinScore bool
+}
+
+// WithMinScore is a functional option that discards hits scoring below the given threshold.
+func WithMinScore(score float64) SearchOption {
+	return func(c *searchConfig) {
+		c.minScore = score
+		c.hasMinScore = true
+	}
+}
+
+// TopK ranks a slice of scores, like the one returned by CosineSimilarity.Do, and returns
+// the k best hits in decreasing score order. Ties are broken by document position, so
+// earlier documents come first. If k <= 0, all hits are returned.
+// Hit IDs are the positions of the scores formatted as strings.
+func TopK(scores []float64, k int, opts ...SearchOption) []Hit {
+	c := newCollector(k, opts...)
+	for i, score := range scores {
+		c.push(i, score)
+	}
+	return c.hits(nil)
+}
+
+// collector selects the best k hits pushed into it using a bounded min-heap,
+// which costs O(n log k) instead of sorting all n hits.
+type collector struct {
+	k   int // Maximum number of retained hits, unbounded if <= 0
+	cfg searchConfig
+	h   hitHeap
+}
+
+// newCollector creates a collector retaining at most k hits.
+func newCollector(k int, opts ...SearchOption) *collector {
+	c := &collector{k: k}
+	for _, opt := range opts {
+		opt(&c.cfg)
+	}
+	return c
+}
+
+// push offers a scored document to the collector.
+func (c *collector) push(doc int, score float64) {
+	if c.cfg.hasMinScore && score < c.cfg.minScore {
+		return
+	}
+	hit := Hit{Doc: doc, Score: score}
+	if c.k <= 0 || len(c.h) < c.k {
+		heap.Push(&c.h, hit)
+		return
+	}
+	// Replace the worst retained hit only if the new one ranks better.
+	if worse(c.h[0], hit) {
+		c.h[0] = hit
+		heap.Fix(&c.h, 0)
+	}
+}
+
+// hits returns the retained hits in decreasing rank order.
+// If ids is not nil, it is used to fill the Hit IDs instead of the document positions.
+func (c *collector) hits(ids []string) []Hit {
+	hits := []Hit(c.h)
+	slices.SortFunc(hits, func(a, b Hit) int {
+		switch {
+		case worse(b, a):
+			return -1
+		case worse(a, b):
+			return 1
+		default:
+			return 0
+		}
+	})
+	for i := range hits {
+		if ids != nil {
+			hits[i].ID = ids[hits[i].Doc]
+		} else {
+			hits[i].ID = strconv.Itoa(hits[i].Doc)
+		}
+	}
+	return hits
+}
+
+// worse reports whether hit a ranks below hit b: it has a lower score or,
+// on equal scores, a later document position.
+func worse(a, b Hit) bool {
+	if a.Score != b.Score {
+		return a.Score < b.Score
+	}
+	return a.Doc > b.Doc
+}
+
+// hitHeap is a min-heap of hits, where the root is the worst retained hit.
+type hitHeap []Hit
+
+func (h hitHeap) Len() int           { return len(h) }
+func (h hitHeap) Less(i, j int) bool { return worse(h[i], h[j]) }
+func (h hitHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *hitHeap) Push(x any)        { *h = append(*h, x.(Hit)) }
+func (h *hitHeap) Pop() any {
+	old := *h
+	hit := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return hit
+}
@@ -0,0 +1,99 @@
+package similarity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rioloc/tfidf-go"
+	"github.com/rioloc/tfidf-go/token"
+)
+
+func TestTopK(t *testing.T) {
+	scores := []float64{0.2, 0.9, 0.5, 0.9, 0.0, 0.5}
+
+	tests := []struct {
+		name     string
+		k        int
+		opts     []SearchOption
+		wantDocs []int
+	}{
+		{
+			name:     "Top 3 with stable ties",
+			k:        3,
+			wantDocs: []int{1, 3, 2},
+		},
+		{
+			name:     "All hits",
+			k:        0,
+			wantDocs: []int{1, 3, 2, 5, 0, 4},
+		},
+		{
+			name:     "k larger than scores",
+			k:        100,
+			wantDocs: []int{1, 3, 2, 5, 0, 4},
+		},
+		{
+			name:     "Minimum score",
+			k:        10,
+			opts:     []SearchOption{WithMinScore(0.5)},
+			wantDocs: []int{1, 3, 2, 5},
+		},
+		{
+			name:     "Minimum score above all",
+			k:        10,
+			opts:     []SearchOption{WithMinScore(1)},
+			wantDocs: []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := TopK(scores, tt.k, tt.opts...)
+			if len(hits) != len(tt.wantDocs) {
+				t.Fatalf("TopK() got %d hits, want %d", len(hits), len(tt.wantDocs))
+			}
+			for i, hit := range hits {
+				if hit.Doc != tt.wantDocs[i] {
+					t.Errorf("hit %d: got doc %d, want %d", i, hit.Doc, tt.wantDocs[i])
+				}
+				if hit.Score != scores[hit.Doc] {
+					t.Errorf("hit %d: got score %v, want %v", i, hit.Score, scores[hit.Doc])
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	documents := []string{
+		"All animals are equal but some animals are more equal than others",
+		"Big Brother is watching you",
+		"If you want a picture of the future imagine a boot stamping on a human face forever",
+		"To be or not to be that is the question",
+	}
+	ids := []string{"animal-farm", "1984-a", "1984-b", "hamlet"}
+
+	tokenizer := token.NewTokenizer(token.WithNormalizeFunc(strings.ToLower))
+	idx, err := NewIndex(tokenizer, tfidf.NewModel(), documents, WithDocumentIDs(ids))
+	if err != nil {
+		t.Fatalf("NewIndex() error: %v", err)
+	}
+
+	hits, err := idx.Search("the future of animals", 2)
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("Search() got %d hits, want 2", len(hits))
+	}
+	if hits[0].ID != "1984-b" || hits[1].ID != "animal-farm" {
+		t.Errorf("Search() got %+v", hits)
+	}
+	if hits[0].Score < hits[1].Score {
+		t.Errorf("Search() hits are not sorted: %+v", hits)
+	}
+
+	if _, err := NewIndex(tokenizer, tfidf.NewModel(), documents, WithDocumentIDs(ids[:1])); err == nil {
+		t.Errorf("NewIndex() expected error on misaligned IDs")
+	}
+}
@@ -0,0 +1,162 @@
+package tfidf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// sklearnVectorizer is the JSON shape of a fitted scikit-learn TfidfVectorizer: its vocabulary_
+// and idf_ attributes, plus the parameters affecting how documents are weighted.
+// It is produced in Python with:
+//
+//	params = vectorizer.get_params()
+//	json.dump({
+//		"vocabulary_": {term: int(j) for term, j in vectorizer.vocabulary_.items()},
+//		"idf_": vectorizer.idf_.tolist(),
+//		"norm": params["norm"],
+//		"sublinear_tf": params["sublinear_tf"],
+//		"binary": params["binary"],
+//		"smooth_idf": params["smooth_idf"],
+//	}, f)
+type sklearnVectorizer struct {
+	Vocabulary  map[string]int  `json:"vocabulary_"`
+	Idf         []float64       `json:"idf_"`
+	Norm        json.RawMessage `json:"norm,omitempty"` // "l1", "l2" or null; l2 if missing
+	SublinearTf bool            `json:"sublinear_tf"`
+	Binary      bool            `json:"binary"`
+	SmoothIdf   *bool           `json:"smooth_idf,omitempty"` // true if missing
+}
+
+// ImportSklearn reads the vocabulary and the IDF vector of a fitted scikit-learn TfidfVectorizer
+// from JSON, and returns a model vectorizing documents like it does. See ExportSklearn for the format.
+// The optional norm, sublinear_tf, binary and smooth_idf parameters select the equivalent
+// normalization level, TF scheme and IDF scheme, with the scikit-learn defaults if missing.
+//
+// scikit-learn does not keep document frequencies, so DocumentFrequencies returns nil and
+// NumDocuments returns 0 on the imported model, until it is refitted.
+// Documents must be tokenized like scikit-learn does, e.g. with token.WithTokenPattern and
+// token.WithNormalizeFunc(strings.ToLower), to get the same vectors.
+//
+// Example:
+//
+//	f, _ := os.Open("sklearn_vectorizer.json")
+//	defer f.Close()
+//	model, err := ImportSklearn(f)
+func ImportSklearn(r io.Reader) (*Model, error) {
+	var sk sklearnVectorizer
+	if err := json.NewDecoder(r).Decode(&sk); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+
+	n := len(sk.Vocabulary)
+	if n == 0 {
+		return nil, fmt.Errorf("%w: empty vocabulary", ErrInvalidFormat)
+	}
+	if len(sk.Idf) != n {
+		return nil, fmt.Errorf("%w: vocabulary and idf lengths don't match", ErrInvalidFormat)
+	}
+	vocabulary := make([]string, n)
+	filled := make([]bool, n)
+	for term, j := range sk.Vocabulary {
+		if j < 0 || j >= n || filled[j] {
+			return nil, fmt.Errorf("%w: invalid index %d for term %q", ErrInvalidFormat, j, term)
+		}
+		vocabulary[j] = term
+		filled[j] = true
+	}
+	for j, idf := range sk.Idf {
+		if idf < 0 || math.IsNaN(idf) || math.IsInf(idf, 0) {
+			return nil, fmt.Errorf("%w: invalid idf %v for term %q", ErrInvalidFormat, idf, vocabulary[j])
+		}
+	}
+
+	v := NewTfIdfVectorizer()
+	switch string(sk.Norm) {
+	case "", `"l2"`:
+		v.NormLevel = L2Norm
+	case `"l1"`:
+		v.NormLevel = L1Norm
+	case "null":
+		v.NormLevel = NoNorm
+	default:
+		return nil, fmt.Errorf("%w: unsupported norm %s", ErrInvalidFormat, sk.Norm)
+	}
+	switch {
+	case sk.Binary:
+		// scikit-learn applies sublinear_tf on top of binary counts, where it has no effect
+		v.TfScheme = BinaryTf
+	case sk.SublinearTf:
+		v.TfScheme = SublinearTf
+	}
+	idfScheme := SmoothIdf
+	if sk.SmoothIdf != nil && !*sk.SmoothIdf {
+		idfScheme = StandardIdf
+	}
+
+	m := NewModel(WithVectorizer(v), WithIdfScheme(idfScheme))
+	m.vocabulary = vocabulary
+	m.index = sk.Vocabulary
+	m.idf = sk.Idf
+	m.fitted = v
+	return m, nil
+}
+
+// ExportSklearn writes the vocabulary and the IDF vector of the fitted model as JSON, in the shape
+// of the vocabulary_ and idf_ attributes of a scikit-learn TfidfVectorizer, together with its
+// norm, sublinear_tf, binary and smooth_idf parameters. In Python, it can be loaded with:
+//
+//	data = json.load(f)
+//	vectorizer = TfidfVectorizer(norm=data["norm"], sublinear_tf=data["sublinear_tf"],
+//		binary=data["binary"], smooth_idf=data["smooth_idf"])
+//	vectorizer.vocabulary_ = data["vocabulary_"]
+//	vectorizer.idf_ = numpy.array(data["idf_"])
+//
+// Returns:
+//   - err: ErrNotFitted if Fit has not been called, an error if the model uses weighting options
+//     scikit-learn does not support, or an error from w
+func (m *Model) ExportSklearn(w io.Writer) error {
+	if !m.Fitted() {
+		return ErrNotFitted
+	}
+	if m.queryVectorizer != nil {
+		return errors.New("query schemes cannot be exported to scikit-learn")
+	}
+
+	sk := sklearnVectorizer{
+		Vocabulary: m.index,
+		Idf:        m.idf,
+	}
+	switch m.vectorizer.NormLevel {
+	case L2Norm:
+		sk.Norm = json.RawMessage(`"l2"`)
+	case L1Norm:
+		sk.Norm = json.RawMessage(`"l1"`)
+	case NoNorm:
+		sk.Norm = json.RawMessage("null")
+	default:
+		return fmt.Errorf("normalization level %d cannot be exported to scikit-learn", m.vectorizer.NormLevel)
+	}
+	switch m.vectorizer.TfScheme {
+	case RawTf:
+	case BinaryTf:
+		sk.Binary = true
+	case SublinearTf:
+		sk.SublinearTf = true
+	default:
+		return fmt.Errorf("TF scheme %d cannot be exported to scikit-learn", m.vectorizer.TfScheme)
+	}
+	var smooth bool
+	switch m.idfScheme {
+	case SmoothIdf:
+		smooth = true
+	case StandardIdf:
+	default:
+		return fmt.Errorf("IDF scheme %d cannot be exported to scikit-learn", m.idfScheme)
+	}
+	sk.SmoothIdf = &smooth
+
+	return json.NewEncoder(w).Encode(sk)
+}
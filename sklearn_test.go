@@ -0,0 +1,175 @@
+package tfidf
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rioloc/tfidf-go/token"
+)
+
+// readmeDocuments is the example corpus of the README.
+var readmeDocuments = []string{
+	"this is a sample document",
+	"this document is another example",
+	"and this is a different one",
+	"WHILE this Is Not NORMALized ProperLY",
+	"and in this example the word example is written at least to times",
+}
+
+func TestImportSklearn(t *testing.T) {
+	f, err := os.Open("testdata/sklearn_readme.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	model, err := ImportSklearn(f)
+	if err != nil {
+		t.Fatalf("ImportSklearn() error = %v", err)
+	}
+
+	wantVocabulary := []string{
+		"and", "another", "at", "different", "document", "example", "in", "is", "least", "normalized", "not",
+		"one", "properly", "sample", "the", "this", "times", "to", "while", "word", "written",
+	}
+	if !slices.Equal(model.Vocabulary(), wantVocabulary) {
+		t.Errorf("Vocabulary() = %v, want %v", model.Vocabulary(), wantVocabulary)
+	}
+	if model.DocumentFrequencies() != nil || model.NumDocuments() != 0 {
+		t.Errorf("imported model has document frequencies %v over %d documents", model.DocumentFrequencies(), model.NumDocuments())
+	}
+
+	// Matrix printed by scikit-learn in the README, rounded to 8 digits
+	want := [][]float64{
+		{0, 0, 0, 0, 0.55607488, 0, 0, 0.32842678, 0, 0, 0, 0, 0, 0.68924048, 0, 0.32842678, 0, 0, 0, 0, 0},
+		{0, 0.60237173, 0, 0, 0.48598972, 0.48598972, 0, 0.28703336, 0, 0, 0, 0, 0, 0, 0, 0.28703336, 0, 0, 0, 0, 0},
+		{0.45785667, 0, 0, 0.56750154, 0, 0, 0, 0.27041752, 0, 0, 0, 0.56750154, 0, 0, 0, 0.27041752, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0.22578084, 0, 0.47382645, 0.47382645, 0, 0.47382645, 0, 0, 0.22578084, 0, 0, 0.47382645, 0, 0},
+		{0.2357807, 0, 0.2922441, 0, 0, 0.4715614, 0.2922441, 0.13925588, 0.2922441, 0, 0, 0, 0, 0, 0.2922441, 0.13925588, 0.2922441, 0.2922441, 0, 0.2922441, 0.2922441},
+	}
+	tokenizer := token.NewTokenizer(token.WithNormalizeFunc(strings.ToLower))
+	_, tokens, err := tokenizer.Tokenize(readmeDocuments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := model.Transform(tokens)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	for i := range want {
+		if !almostEqualSlices(got[i], want[i], 1e-6) {
+			t.Errorf("Transform()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestModel_ExportSklearn(t *testing.T) {
+	tokenizer := token.NewTokenizer(token.WithNormalizeFunc(strings.ToLower))
+	_, tokens, err := tokenizer.Tokenize(readmeDocuments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []ModelOption
+		wantErr bool
+	}{
+		{name: "defaults"},
+		{name: "l1 sublinear standard idf", opts: []ModelOption{
+			WithVectorizer(NewTfIdfVectorizer(WithNormLevel(L1Norm), WithTfScheme(SublinearTf))), WithSmoothing(false),
+		}},
+		{name: "no norm binary", opts: []ModelOption{
+			WithVectorizer(NewTfIdfVectorizer(WithNormLevel(NoNorm), WithTfScheme(BinaryTf))),
+		}},
+		{name: "pivoted norm", opts: []ModelOption{
+			WithVectorizer(NewTfIdfVectorizer(WithNormLevel(PivotedNorm))),
+		}, wantErr: true},
+		{name: "augmented tf", opts: []ModelOption{
+			WithVectorizer(NewTfIdfVectorizer(WithTfScheme(AugmentedTf))),
+		}, wantErr: true},
+		{name: "bm25 idf", opts: []ModelOption{WithIdfScheme(BM25Idf)}, wantErr: true},
+		{name: "max idf", opts: []ModelOption{WithIdfScheme(MaxIdf)}, wantErr: true},
+		{name: "smart", opts: []ModelOption{WithSmart(SmartScheme{}, SmartScheme{})}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewModel(tt.opts...)
+			want, err := model.FitTransform(tokens)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			err = model.ExportSklearn(&buf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExportSklearn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			imported, err := ImportSklearn(&buf)
+			if err != nil {
+				t.Fatalf("ImportSklearn() error = %v", err)
+			}
+			if !slices.Equal(imported.Vocabulary(), model.Vocabulary()) {
+				t.Errorf("Vocabulary() = %v, want %v", imported.Vocabulary(), model.Vocabulary())
+			}
+			got, err := imported.Transform(tokens)
+			if err != nil {
+				t.Fatalf("Transform() error = %v", err)
+			}
+			for i := range want {
+				if !almostEqualSlices(got[i], want[i], 1e-12) {
+					t.Errorf("Transform()[%d] = %v, want %v", i, got[i], want[i])
+				}
+			}
+
+			// The imported model can be persisted like a fitted one
+			var saved bytes.Buffer
+			if err := imported.Save(&saved, nil); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+			loaded, err := LoadModel(&saved, nil)
+			if err != nil {
+				t.Fatalf("LoadModel() error = %v", err)
+			}
+			if loaded.DocumentFrequencies() != nil {
+				t.Errorf("DocumentFrequencies() = %v, want nil", loaded.DocumentFrequencies())
+			}
+		})
+	}
+
+	if err := NewModel().ExportSklearn(&bytes.Buffer{}); !errors.Is(err, ErrNotFitted) {
+		t.Errorf("ExportSklearn() on unfitted model error = %v, want ErrNotFitted", err)
+	}
+}
+
+func TestImportSklearn_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "not json", input: "vocabulary"},
+		{name: "empty vocabulary", input: `{"vocabulary_": {}, "idf_": []}`},
+		{name: "length mismatch", input: `{"vocabulary_": {"a": 0, "b": 1}, "idf_": [1]}`},
+		{name: "duplicate index", input: `{"vocabulary_": {"a": 0, "b": 0}, "idf_": [1, 1]}`},
+		{name: "duplicate index of empty term", input: `{"vocabulary_": {"": 0, "b": 0}, "idf_": [1, 1]}`},
+		{name: "negative idf", input: `{"vocabulary_": {"a": 0, "b": 1}, "idf_": [1, -1]}`},
+		{name: "index out of range", input: `{"vocabulary_": {"a": 0, "b": 2}, "idf_": [1, 1]}`},
+		{name: "unsupported norm", input: `{"vocabulary_": {"a": 0}, "idf_": [1], "norm": "max"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ImportSklearn(strings.NewReader(tt.input)); !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("ImportSklearn() error = %v, want ErrInvalidFormat", err)
+			}
+		})
+	}
+}
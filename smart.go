@@ -0,0 +1,127 @@
+package tfidf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SmartScheme is a weighting scheme in the SMART notation used by IR systems: a triple of letters
+// selecting the term frequency, the document frequency and the normalization, like "ltc".
+//
+// Supported letters:
+//   - term frequency: n (RawTf), b (BinaryTf), l (SublinearTf), a (AugmentedTf), L (LogAverageTf)
+//   - document frequency: n (NoIdf), t (PlainIdf), p (ProbabilisticIdf)
+//   - normalization: n (NoNorm), c (L2Norm), u (PivotedUniqueNorm)
+type SmartScheme struct {
+	Tf   TfScheme
+	Idf  IdfScheme
+	Norm NLevel
+}
+
+var (
+	smartTf = map[byte]TfScheme{
+		'n': RawTf, 'b': BinaryTf, 'l': SublinearTf, 'a': AugmentedTf, 'L': LogAverageTf,
+	}
+	smartIdf = map[byte]IdfScheme{
+		'n': NoIdf, 't': PlainIdf, 'p': ProbabilisticIdf,
+	}
+	smartNorm = map[byte]NLevel{
+		'n': NoNorm, 'c': L2Norm, 'u': PivotedUniqueNorm,
+	}
+)
+
+// ParseSmartScheme parses a SMART triple like "ltc".
+//
+// Returns:
+//   - scheme: The TF, IDF and normalization choices selected by the letters
+//   - err: Error if the triple is not 3 letters long or contains an unsupported letter
+func ParseSmartScheme(triple string) (SmartScheme, error) {
+	if len(triple) != 3 {
+		return SmartScheme{}, fmt.Errorf("invalid SMART scheme %q: expected 3 letters", triple)
+	}
+	tf, ok := smartTf[triple[0]]
+	if !ok {
+		return SmartScheme{}, fmt.Errorf("invalid SMART scheme %q: unsupported term frequency %q", triple, triple[0])
+	}
+	idf, ok := smartIdf[triple[1]]
+	if !ok {
+		return SmartScheme{}, fmt.Errorf("invalid SMART scheme %q: unsupported document frequency %q", triple, triple[1])
+	}
+	norm, ok := smartNorm[triple[2]]
+	if !ok {
+		return SmartScheme{}, fmt.Errorf("invalid SMART scheme %q: unsupported normalization %q", triple, triple[2])
+	}
+	return SmartScheme{Tf: tf, Idf: idf, Norm: norm}, nil
+}
+
+// ParseSmart parses a SMART notation "ddd.qqq", where the first triple is the document scheme
+// and the second one the query scheme, like "lnc.ltc". A single triple is used for both.
+//
+// Example:
+//
+//	doc, query, _ := ParseSmart("lnc.ltc")
+//	model := NewModel(WithSmart(doc, query))
+func ParseSmart(notation string) (doc, query SmartScheme, err error) {
+	docTriple, queryTriple, found := strings.Cut(notation, ".")
+	if !found {
+		queryTriple = docTriple
+	}
+	if doc, err = ParseSmartScheme(docTriple); err != nil {
+		return SmartScheme{}, SmartScheme{}, err
+	}
+	if query, err = ParseSmartScheme(queryTriple); err != nil {
+		return SmartScheme{}, SmartScheme{}, err
+	}
+	return doc, query, nil
+}
+
+// String returns the scheme in SMART notation, or "?" for choices the notation cannot express.
+func (s SmartScheme) String() string {
+	return string([]byte{smartLetter(smartTf, s.Tf), smartLetter(smartIdf, s.Idf), smartLetter(smartNorm, s.Norm)})
+}
+
+// smartLetter returns the SMART letter mapped to a choice, or '?' if there is none.
+func smartLetter[T comparable](letters map[byte]T, choice T) byte {
+	for letter, c := range letters {
+		if c == choice {
+			return letter
+		}
+	}
+	return '?'
+}
+
+// WithSmartScheme sets the TF scheme and the normalization level of the vectorizer from a SMART scheme.
+// The document frequency letter is not part of the vectorizer: it selects the IdfScheme
+// to compute the IDF vector with, see IdfFromDf and WithSmart.
+//
+// Example:
+//
+//	scheme, _ := ParseSmartScheme("ltc")
+//	vectorizer := NewTfIdfVectorizer(WithSmartScheme(scheme))
+//	idfVec, _ := IdfFromDf(Df(vocabulary, tokens), len(tokens), scheme.Idf)
+//	tfIdfMat, _ := vectorizer.TfIdf(Tf(vocabulary, tokens), idfVec)
+func WithSmartScheme(scheme SmartScheme) TfIdfOption {
+	return func(t *TfIdfVectorizer) {
+		t.TfScheme = scheme.Tf
+		t.NormLevel = scheme.Norm
+	}
+}
+
+// WithSmart configures the model with separate SMART schemes for documents and queries:
+// Transform weights documents with doc, while TransformQuery weights queries with query.
+// Both IDF vectors are computed from the same fitted document frequencies.
+// opts are applied to both vectorizers, e.g. to set the pivot of PivotedUniqueNorm.
+// It replaces any previous WithVectorizer, WithSmoothing or WithIdfScheme option.
+//
+// Example:
+//
+//	doc, query, _ := ParseSmart("lnu.ltc")
+//	model := NewModel(WithSmart(doc, query, WithPivot(0.25, 0)))
+func WithSmart(doc, query SmartScheme, opts ...TfIdfOption) ModelOption {
+	return func(m *Model) {
+		m.vectorizer = NewTfIdfVectorizer(append([]TfIdfOption{WithSmartScheme(doc)}, opts...)...)
+		m.idfScheme = doc.Idf
+		m.queryVectorizer = NewTfIdfVectorizer(append([]TfIdfOption{WithSmartScheme(query)}, opts...)...)
+		m.queryIdfScheme = query.Idf
+	}
+}
@@ -0,0 +1,179 @@
+package tfidf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseSmart(t *testing.T) {
+	tests := []struct {
+		notation  string
+		wantDoc   SmartScheme
+		wantQuery SmartScheme
+		wantErr   bool
+	}{
+		{
+			notation:  "lnc.ltc",
+			wantDoc:   SmartScheme{Tf: SublinearTf, Idf: NoIdf, Norm: L2Norm},
+			wantQuery: SmartScheme{Tf: SublinearTf, Idf: PlainIdf, Norm: L2Norm},
+		},
+		{
+			notation:  "Lnu.apn",
+			wantDoc:   SmartScheme{Tf: LogAverageTf, Idf: NoIdf, Norm: PivotedUniqueNorm},
+			wantQuery: SmartScheme{Tf: AugmentedTf, Idf: ProbabilisticIdf, Norm: NoNorm},
+		},
+		{
+			notation:  "bnn",
+			wantDoc:   SmartScheme{Tf: BinaryTf, Idf: NoIdf, Norm: NoNorm},
+			wantQuery: SmartScheme{Tf: BinaryTf, Idf: NoIdf, Norm: NoNorm},
+		},
+		{notation: "ltc.lt", wantErr: true},
+		{notation: "xtc", wantErr: true},
+		{notation: "lxc", wantErr: true},
+		{notation: "ltx", wantErr: true},
+		{notation: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			doc, query, err := ParseSmart(tt.notation)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseSmart() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSmart error: %v", err)
+			}
+			if doc != tt.wantDoc || query != tt.wantQuery {
+				t.Errorf("got %+v.%+v, want %+v.%+v", doc, query, tt.wantDoc, tt.wantQuery)
+			}
+			if got := doc.String() + "." + query.String(); len(tt.notation) == 7 && got != tt.notation {
+				t.Errorf("String: got %q, want %q", got, tt.notation)
+			}
+		})
+	}
+
+	if got := (SmartScheme{Tf: LengthTf, Idf: SmoothIdf, Norm: L1Norm}).String(); got != "???" {
+		t.Errorf("String: got %q, want %q", got, "???")
+	}
+}
+
+func TestModel_WithSmart(t *testing.T) {
+	tokens := [][]string{
+		{"the", "cat", "sat", "on", "the", "mat"},
+		{"the", "dog", "sat"},
+		{"a", "cat", "and", "a", "dog"},
+	}
+	query := [][]string{{"cat", "cat", "dog"}}
+
+	doc, q, err := ParseSmart("lnc.ltc")
+	if err != nil {
+		t.Fatalf("ParseSmart error: %v", err)
+	}
+	m := NewModel(WithSmart(doc, q))
+	docs, err := m.FitTransform(tokens)
+	if err != nil {
+		t.Fatalf("FitTransform error: %v", err)
+	}
+
+	// Documents: 1 + log(tf), no IDF, cosine normalization
+	ones := make([]float64, len(m.Vocabulary()))
+	for j := range ones {
+		ones[j] = 1
+	}
+	want, _ := NewTfIdfVectorizer(WithTfScheme(SublinearTf)).TfIdf(Tf(m.Vocabulary(), tokens), ones)
+	for i := range want {
+		if !almostEqualSlices(docs[i], want[i], tol) {
+			t.Errorf("doc %d: got %v, want %v", i, docs[i], want[i])
+		}
+	}
+
+	// Queries: 1 + log(tf), log(N / df), cosine normalization
+	got, err := m.TransformQuery(query)
+	if err != nil {
+		t.Fatalf("TransformQuery error: %v", err)
+	}
+	idfVec, _ := IdfFromDf(m.DocumentFrequencies(), 3, PlainIdf)
+	wantQuery, _ := NewTfIdfVectorizer(WithTfScheme(SublinearTf)).TfIdf(Tf(m.Vocabulary(), query), idfVec)
+	if !almostEqualSlices(got[0], wantQuery[0], tol) {
+		t.Errorf("query: got %v, want %v", got[0], wantQuery[0])
+	}
+
+	// Without a query scheme, queries are weighted like documents
+	m = NewModel()
+	if err := m.Fit(tokens); err != nil {
+		t.Fatalf("Fit error: %v", err)
+	}
+	got, _ = m.TransformQuery(query)
+	wantQuery, _ = m.Transform(query)
+	if !almostEqualSlices(got[0], wantQuery[0], tol) {
+		t.Errorf("default query: got %v, want %v", got[0], wantQuery[0])
+	}
+}
+
+func TestPivotedUniqueNorm(t *testing.T) {
+	tokens := [][]string{
+		{"a", "b", "c", "d"},
+		{"a", "b"},
+	}
+	vocabulary := []string{"a", "b", "c", "d"}
+	ones := []float64{1, 1, 1, 1}
+
+	// Average unique terms: 3, slope 0.2: factors 0.8*3+0.2*4 = 3.2 and 0.8*3+0.2*2 = 2.8
+	v := NewTfIdfVectorizer(WithNormLevel(PivotedUniqueNorm))
+	got, err := v.TfIdf(Tf(vocabulary, tokens), ones)
+	if err != nil {
+		t.Fatalf("TfIdf error: %v", err)
+	}
+	want := [][]float64{{1 / 3.2, 1 / 3.2, 1 / 3.2, 1 / 3.2}, {1 / 2.8, 1 / 2.8, 0, 0}}
+	for i := range want {
+		if !almostEqualSlices(got[i], want[i], tol) {
+			t.Errorf("row %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+	sparse, err := v.TfIdfSparse(TfSparse(vocabulary, tokens), ones)
+	if err != nil {
+		t.Fatalf("TfIdfSparse error: %v", err)
+	}
+	for i, row := range sparse.Dense() {
+		if !almostEqualSlices(row, want[i], tol) {
+			t.Errorf("sparse row %d: got %v, want %v", i, row, want[i])
+		}
+	}
+
+	// Terms with a zero IDF still count as distinct terms of the document
+	zeros := []float64{0, 0, 1, 1}
+	got, _ = v.TfIdf(Tf(vocabulary, tokens), zeros)
+	sparse, _ = v.TfIdfSparse(TfSparse(vocabulary, tokens), zeros)
+	want = [][]float64{{0, 0, 1 / 3.2, 1 / 3.2}, {0, 0, 0, 0}}
+	for i, row := range sparse.Dense() {
+		if !almostEqualSlices(got[i], want[i], tol) || !almostEqualSlices(row, want[i], tol) {
+			t.Errorf("zero IDF row %d: got %v and %v, want %v", i, got[i], row, want[i])
+		}
+	}
+
+	// A fixed pivot is used as is: 0.5*2 + 0.5*2 = 2
+	v = NewTfIdfVectorizer(WithNormLevel(PivotedUniqueNorm), WithPivot(0.5, 2))
+	got, _ = v.TfIdf(Tf(vocabulary, tokens[1:]), ones)
+	if !almostEqualSlices(got[0], []float64{0.5, 0.5, 0, 0}, tol) {
+		t.Errorf("fixed pivot: got %v", got[0])
+	}
+
+	// A model keeps the pivot of the fitted corpus for new documents
+	doc, _, _ := ParseSmart("nnu")
+	m := NewModel(WithSmart(doc, doc))
+	if err := m.Fit(tokens); err != nil {
+		t.Fatalf("Fit error: %v", err)
+	}
+	for _, transform := range []func([][]string) ([][]float64, error){m.Transform, m.TransformQuery} {
+		got, err := transform([][]string{{"a"}})
+		if err != nil {
+			t.Fatalf("Transform error: %v", err)
+		}
+		if wantA := 1 / (0.8*3 + 0.2); math.Abs(got[0][0]-wantA) > tol {
+			t.Errorf("fitted pivot: got %v, want %v", got[0][0], wantA)
+		}
+	}
+}
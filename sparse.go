@@ -0,0 +1,340 @@
+package tfidf
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/rioloc/tfidf-go/internal/parallel"
+)
+
+// SparseMatrix is a matrix stored in Compressed Sparse Row (CSR) format.
+// Only non-zero values are stored, which keeps memory proportional to the number
+// of distinct terms per document rather than to documents × vocabulary.
+//
+// The values of row i are Values[Indptr[i]:Indptr[i+1]], and their column
+// positions are Indices[Indptr[i]:Indptr[i+1]], sorted in increasing order.
+type SparseMatrix struct {
+	Cols    int       // Number of columns, e.g. the vocabulary size
+	Indptr  []int     // Row pointers, len(Indptr) = rows + 1
+	Indices []int     // Column index of each stored value
+	Values  []float64 // Stored non-zero values
+
+	stats []docStats // Statistics of the whole documents of the first rows, when counted from tokens
+}
+
+// SparseVector is a single sparse row: Values[k] is the value at column Indices[k].
+// Indices are sorted in increasing order.
+type SparseVector struct {
+	Indices []int
+	Values  []float64
+}
+
+// NewSparseMatrix creates an empty sparse matrix with the given number of columns.
+// Rows are added with AppendRow.
+func NewSparseMatrix(cols int) *SparseMatrix {
+	return &SparseMatrix{
+		Cols:   cols,
+		Indptr: []int{0},
+	}
+}
+
+// SparseFromDense converts a dense matrix [rows][cols] into CSR format, skipping zero values.
+func SparseFromDense(mat [][]float64) *SparseMatrix {
+	var cols int
+	if len(mat) > 0 {
+		cols = len(mat[0])
+	}
+	s := NewSparseMatrix(cols)
+	for _, row := range mat {
+		for j, val := range row {
+			if val != 0 {
+				s.Indices = append(s.Indices, j)
+				s.Values = append(s.Values, val)
+			}
+		}
+		s.Indptr = append(s.Indptr, len(s.Indices))
+	}
+	return s
+}
+
+// AppendRow appends a row to the matrix. Indices must be sorted in increasing order
+// and must be lower than Cols; indices and values must have the same length.
+func (s *SparseMatrix) AppendRow(indices []int, values []float64) {
+	s.Indices = append(s.Indices, indices...)
+	s.Values = append(s.Values, values...)
+	s.Indptr = append(s.Indptr, len(s.Indices))
+}
+
+// Rows returns the number of rows in the matrix.
+func (s *SparseMatrix) Rows() int {
+	return len(s.Indptr) - 1
+}
+
+// NNZ returns the number of stored (non-zero) values.
+func (s *SparseMatrix) NNZ() int {
+	return len(s.Values)
+}
+
+// Row returns row i as a SparseVector. The returned slices share memory with the matrix.
+func (s *SparseMatrix) Row(i int) SparseVector {
+	start, end := s.Indptr[i], s.Indptr[i+1]
+	return SparseVector{
+		Indices: s.Indices[start:end:end],
+		Values:  s.Values[start:end:end],
+	}
+}
+
+// Dense converts the matrix into a dense [rows][cols] matrix.
+func (s *SparseMatrix) Dense() [][]float64 {
+	mat := make([][]float64, s.Rows())
+	for i := range mat {
+		mat[i] = s.Row(i).Dense(s.Cols)
+	}
+	return mat
+}
+
+// Dense converts the vector into a dense vector of length n.
+func (v SparseVector) Dense(n int) []float64 {
+	vec := make([]float64, n)
+	for k, j := range v.Indices {
+		vec[j] = v.Values[k]
+	}
+	return vec
+}
+
+// Dot returns the dot product between two sparse vectors.
+// Both vectors are walked in a single merge pass over their sorted indices.
+func (v SparseVector) Dot(o SparseVector) float64 {
+	var dot float64
+	a, b := 0, 0
+	for a < len(v.Indices) && b < len(o.Indices) {
+		switch {
+		case v.Indices[a] < o.Indices[b]:
+			a++
+		case v.Indices[a] > o.Indices[b]:
+			b++
+		default:
+			dot += v.Values[a] * o.Values[b]
+			a++
+			b++
+		}
+	}
+	return dot
+}
+
+// TfSparse calculates the Term Frequency matrix in CSR format.
+// It returns the same counts as Tf, storing only the terms that occur in each document.
+// The matrix also keeps the length of each document and the counts of its terms missing
+// from the vocabulary, so that TfIdfSparse weights them like the whole documents.
+//
+// Parameters:
+//   - vocabulary: Ordered list of unique terms across all documents
+//   - tokens: Tokenized documents where tokens[i] contains all tokens for document i
+//
+// Returns:
+//   - Sparse term frequency matrix [documents][terms]
+func TfSparse(vocabulary []string, tokens [][]string) *SparseMatrix {
+	index := make(map[string]int, len(vocabulary))
+	for j, term := range vocabulary {
+		index[term] = j
+	}
+	// The background context is never done, so no error can occur
+	tf, _ := tfSparse(context.Background(), index, len(vocabulary), tokens, 0)
+	return tf
+}
+
+// TfSparse is like the package-level TfSparse, but shards documents across the vectorizer's Workers.
+func (t *TfIdfVectorizer) TfSparse(vocabulary []string, tokens [][]string) *SparseMatrix {
+	tf, _ := t.TfSparseContext(context.Background(), vocabulary, tokens)
+	return tf
+}
+
+// TfSparseContext is like TfSparse, but checks ctx between documents and returns ctx.Err()
+// as soon as ctx is done.
+func (t *TfIdfVectorizer) TfSparseContext(ctx context.Context, vocabulary []string, tokens [][]string) (*SparseMatrix, error) {
+	index := make(map[string]int, len(vocabulary))
+	for j, term := range vocabulary {
+		index[term] = j
+	}
+	return tfSparse(ctx, index, len(vocabulary), tokens, t.Workers)
+}
+
+// tfSparse counts the terms of each document using a term -> column index.
+// Tokens missing from the index are not stored, but are part of the document statistics.
+// Documents are sharded across workers, and the rows of every shard are appended in document order.
+// It returns ctx.Err() if ctx is done before all documents are counted.
+func tfSparse(ctx context.Context, index map[string]int, cols int, tokens [][]string, workers int) (*SparseMatrix, error) {
+	shards := make([]*SparseMatrix, parallel.Workers(workers, len(tokens)))
+	err := parallel.For(workers, len(tokens), func(shard, lo, hi int) error {
+		s := NewSparseMatrix(cols)
+		for _, doc := range tokens[lo:hi] {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			// Count every term of this document, then keep the indexed ones by column
+			termCounts := make(map[string]float64)
+			for _, term := range doc {
+				termCounts[term]++
+			}
+			var stats docStats
+			counts := make(map[int]float64)
+			for term, count := range termCounts {
+				stats.add(count)
+				if j, found := index[term]; found {
+					counts[j] = count
+				}
+			}
+			s.stats = append(s.stats, stats)
+
+			// Emit the row with sorted column indices
+			start := len(s.Indices)
+			for j := range counts {
+				s.Indices = append(s.Indices, j)
+			}
+			slices.Sort(s.Indices[start:])
+			for _, j := range s.Indices[start:] {
+				s.Values = append(s.Values, counts[j])
+			}
+			s.Indptr = append(s.Indptr, len(s.Indices))
+		}
+		shards[shard] = s
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s := shards[0]
+	for _, shard := range shards[1:] {
+		s.appendRows(shard)
+	}
+	return s, nil
+}
+
+// appendRows appends all rows of o, which must have the same number of columns, to the matrix.
+// The document statistics of o are kept only if every row of the matrix has its own.
+func (s *SparseMatrix) appendRows(o *SparseMatrix) {
+	if len(s.stats) == s.Rows() {
+		s.stats = append(s.stats, o.stats...)
+	}
+	offset := len(s.Indices)
+	for _, end := range o.Indptr[1:] {
+		s.Indptr = append(s.Indptr, offset+end)
+	}
+	s.Indices = append(s.Indices, o.Indices...)
+	s.Values = append(s.Values, o.Values...)
+}
+
+// TfIdfSparse computes the TF-IDF matrix in CSR format. It is the sparse counterpart
+// of TfIdf: each stored value is weighted according to the vectorizer's TfScheme and multiplied
+// by the IDF of its term, and each row is normalized according to the vectorizer's NormLevel.
+//
+// Parameters:
+//   - tf: Sparse term frequency matrix [documents][terms] from TfSparse()
+//   - idfVec: Inverse document frequency vector [terms] from Idf()
+//
+// Returns:
+//   - tfIdfMat: Sparse TF-IDF matrix [documents][terms]; tf is not modified
+//   - err: Error if normalization fails or input dimensions don't match
+//
+// With a matrix from TfSparse, the document length, max and average counts some schemes use
+// are those of the whole documents; otherwise, those of the stored counts of each row.
+func (t *TfIdfVectorizer) TfIdfSparse(tf *SparseMatrix, idfVec []float64) (tfIdfMat *SparseMatrix, err error) {
+	return t.TfIdfSparseContext(context.Background(), tf, idfVec)
+}
+
+// TfIdfSparseContext is like TfIdfSparse, but checks ctx between documents and returns ctx.Err()
+// as soon as ctx is done.
+func (t *TfIdfVectorizer) TfIdfSparseContext(ctx context.Context, tf *SparseMatrix, idfVec []float64) (tfIdfMat *SparseMatrix, err error) {
+	if tf.Rows() == 0 {
+		return nil, errors.New("empty TF matrix")
+	}
+	if tf.Cols != len(idfVec) {
+		return nil, errors.New("TF matrix and IDF vector dimensions don't match")
+	}
+
+	tfIdfMat, err = t.weightSparse(ctx, tf, idfVec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Zero values do not contribute to any norm, so the stored values of each row
+	// can be normalized in place as if they were the whole document vector
+	norm := t
+	if t.pivoted() {
+		if norm, err = t.withCorpusPivot(ctx, tfIdfMat.rowValues(), tf.rowValues()); err != nil {
+			return nil, err
+		}
+	}
+	if err := norm.normalizeRows(ctx, tfIdfMat.rowValues(), tf.rowValues()); err != nil {
+		return nil, err
+	}
+
+	return tfIdfMat, nil
+}
+
+// weightSparse applies the TF scheme and the IDF to the term counts of tf, without normalizing.
+// Zero values do not contribute to any TF weighting, so the stored values of each row
+// can be processed in place as if they were the whole document vector.
+// It returns ctx.Err() if ctx is done before all documents are weighted.
+func (t *TfIdfVectorizer) weightSparse(ctx context.Context, tf *SparseMatrix, idfVec []float64) (*SparseMatrix, error) {
+	weighted := &SparseMatrix{
+		Cols:    tf.Cols,
+		Indptr:  slices.Clone(tf.Indptr),
+		Indices: slices.Clone(tf.Indices),
+		Values:  slices.Clone(tf.Values),
+	}
+	err := parallel.For(t.Workers, weighted.Rows(), func(_, lo, hi int) error {
+		for i := lo; i < hi; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			row := weighted.Row(i)
+			if err := t.TfScheme.weight(row.Values, tf.rowStats(i)); err != nil {
+				return err
+			}
+			for k, j := range row.Indices {
+				row.Values[k] *= idfVec[j]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return weighted, nil
+}
+
+// withFittedPivot is like withCorpusPivot, but computes the pivot from the term counts of a corpus,
+// so that documents vectorized later are normalized against the statistics of that corpus.
+// tf may be nil if the vectorizer does not learn a pivot.
+// It returns ctx.Err() if ctx is done before all documents are weighted.
+func (t *TfIdfVectorizer) withFittedPivot(ctx context.Context, tf *SparseMatrix, idfVec []float64) (*TfIdfVectorizer, error) {
+	if !t.pivoted() || t.Pivot != 0 {
+		return t, nil
+	}
+	weighted, err := t.weightSparse(ctx, tf, idfVec)
+	if err != nil {
+		return nil, err
+	}
+	return t.withCorpusPivot(ctx, weighted.rowValues(), tf.rowValues())
+}
+
+// rowValues returns the stored values of every row. The returned slices share memory with the matrix.
+func (s *SparseMatrix) rowValues() [][]float64 {
+	values := make([][]float64, s.Rows())
+	for i := range values {
+		values[i] = s.Row(i).Values
+	}
+	return values
+}
+
+// rowStats returns the statistics of the document of row i: those counted from its tokens,
+// if the row was counted by TfSparse, otherwise those of its stored counts.
+func (s *SparseMatrix) rowStats(i int) docStats {
+	if i < len(s.stats) {
+		return s.stats[i]
+	}
+	return countStats(s.Row(i).Values)
+}
@@ -0,0 +1,122 @@
+package tfidf
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestSparseMatrix_Dense(t *testing.T) {
+	dense := [][]float64{
+		{0, 1, 0, 2},
+		{0, 0, 0, 0},
+		{3, 0, 0, 4},
+	}
+
+	s := SparseFromDense(dense)
+	if s.Rows() != 3 || s.Cols != 4 || s.NNZ() != 4 {
+		t.Fatalf("got rows=%d cols=%d nnz=%d, want 3, 4, 4", s.Rows(), s.Cols, s.NNZ())
+	}
+	if !slices.Equal(s.Indptr, []int{0, 2, 2, 4}) {
+		t.Errorf("indptr: got %v", s.Indptr)
+	}
+	if row := s.Row(2); !slices.Equal(row.Indices, []int{0, 3}) || !slices.Equal(row.Values, []float64{3, 4}) {
+		t.Errorf("row 2: got %v", row)
+	}
+
+	got := s.Dense()
+	for i := range dense {
+		if !almostEqualSlices(got[i], dense[i], tol) {
+			t.Errorf("row %d: got %v, want %v", i, got[i], dense[i])
+		}
+	}
+}
+
+func TestSparseVector_Dot(t *testing.T) {
+	tests := []struct {
+		name string
+		a    SparseVector
+		b    SparseVector
+		want float64
+	}{
+		{
+			name: "Overlapping",
+			a:    SparseVector{Indices: []int{0, 2, 5}, Values: []float64{1, 2, 3}},
+			b:    SparseVector{Indices: []int{2, 3, 5}, Values: []float64{4, 5, 6}},
+			want: 2*4 + 3*6,
+		},
+		{
+			name: "Disjoint",
+			a:    SparseVector{Indices: []int{0, 1}, Values: []float64{1, 1}},
+			b:    SparseVector{Indices: []int{2, 3}, Values: []float64{1, 1}},
+			want: 0,
+		},
+		{
+			name: "Empty",
+			a:    SparseVector{},
+			b:    SparseVector{Indices: []int{1}, Values: []float64{1}},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Dot(tt.b); math.Abs(got-tt.want) > tol {
+				t.Errorf("Dot() got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTfIdfVectorizer_TfIdfSparse(t *testing.T) {
+	vocab := []string{"a", "cat", "dog", "sat", "the"}
+	tokens := [][]string{
+		{"the", "cat", "sat"},
+		{"the", "dog", "sat", "the"},
+		{"a", "cat"},
+		{},
+	}
+	idf := Idf(vocab, tokens, true)
+
+	tfSparse := TfSparse(vocab, tokens)
+	tf := Tf(vocab, tokens)
+	for i, row := range tfSparse.Dense() {
+		if !almostEqualSlices(row, tf[i], tol) {
+			t.Errorf("tf row %d: got %v, want %v", i, row, tf[i])
+		}
+	}
+
+	for _, lvl := range []NLevel{NoNorm, L1Norm, L2Norm} {
+		vec := NewTfIdfVectorizer(WithNormLevel(lvl))
+		want, err := vec.TfIdf(tf, idf)
+		if err != nil {
+			t.Fatalf("TfIdf error: %v", err)
+		}
+		got, err := vec.TfIdfSparse(tfSparse, idf)
+		if err != nil {
+			t.Fatalf("TfIdfSparse error: %v", err)
+		}
+		for i, row := range got.Dense() {
+			if !almostEqualSlices(row, want[i], tol) {
+				t.Errorf("level %d row %d: got %v, want %v", lvl, i, row, want[i])
+			}
+		}
+	}
+
+	// The input TF matrix must be left untouched
+	for i, row := range tfSparse.Dense() {
+		if !almostEqualSlices(row, tf[i], tol) {
+			t.Errorf("tf row %d modified: got %v, want %v", i, row, tf[i])
+		}
+	}
+}
+
+func TestTfIdfVectorizer_TfIdfSparse_Errors(t *testing.T) {
+	vec := NewTfIdfVectorizer()
+	if _, err := vec.TfIdfSparse(NewSparseMatrix(2), []float64{1, 1}); err == nil {
+		t.Errorf("expected error on empty matrix")
+	}
+	if _, err := vec.TfIdfSparse(SparseFromDense([][]float64{{1, 1}}), []float64{1}); err == nil {
+		t.Errorf("expected error on dimension mismatch")
+	}
+}
@@ -0,0 +1,127 @@
+package tfidf
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// TokenIterator yields tokenized documents one at a time, so that a corpus can be fitted
+// without holding all of its documents in memory.
+// Next returns io.EOF once all documents have been returned.
+type TokenIterator interface {
+	Next() (tokens []string, err error)
+}
+
+// TokenIteratorFunc is an adapter to use an ordinary function as a TokenIterator.
+type TokenIteratorFunc func() ([]string, error)
+
+// Next calls f().
+func (f TokenIteratorFunc) Next() ([]string, error) {
+	return f()
+}
+
+// ChanIterator returns a TokenIterator reading tokenized documents from a channel,
+// until the channel is closed.
+//
+// Example:
+//
+//	docs := make(chan []string)
+//	go func() {
+//		defer close(docs)
+//		for _, doc := range documents {
+//			docs <- tokenize(doc)
+//		}
+//	}()
+//	err := model.FitStream(ChanIterator(docs))
+func ChanIterator(docs <-chan []string) TokenIterator {
+	return TokenIteratorFunc(func() ([]string, error) {
+		tokens, ok := <-docs
+		if !ok {
+			return nil, io.EOF
+		}
+		return tokens, nil
+	})
+}
+
+// readerTokenizer is the interface for tokenizers that can tokenize a single document read
+// from an io.Reader, like token.Tokenizer and token.CharNGramTokenizer.
+type readerTokenizer interface {
+	TokenizeReader(r io.Reader) ([]string, error)
+}
+
+// ReaderIterator returns a TokenIterator tokenizing one document per io.Reader.
+// next returns the reader of the following document, or io.EOF once all documents have been returned;
+// each reader is read to the end before next is called again, and is closed if it implements io.Closer.
+//
+// Example:
+//
+//	k := 0
+//	it := ReaderIterator(tokenizer, func() (io.Reader, error) {
+//		if k == len(paths) {
+//			return nil, io.EOF
+//		}
+//		k++
+//		return os.Open(paths[k-1])
+//	})
+//	err := model.FitStream(it)
+func ReaderIterator(tokenizer readerTokenizer, next func() (io.Reader, error)) TokenIterator {
+	return TokenIteratorFunc(func() ([]string, error) {
+		r, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if c, ok := r.(io.Closer); ok {
+			defer c.Close()
+		}
+		return tokenizer.TokenizeReader(r)
+	})
+}
+
+// FitStream is like Fit, but consumes the documents one at a time from an iterator:
+// only the document and corpus frequencies of the terms are kept in memory, never the documents,
+// so that corpora larger than the available memory can be fitted.
+//
+// The corpus pivot of pivoted normalizations cannot be learned without a second pass over the
+// documents: with PivotedUniqueNorm or PivotedNorm, the pivot must be set with WithPivot.
+//
+// Returns:
+//   - err: Error if the corpus is empty, if the iterator fails, if a pivot must be learned,
+//     or any error from Fit
+//
+// Example:
+//
+//	err := model.FitStream(ChanIterator(docs))
+func (m *Model) FitStream(docs TokenIterator) error {
+	return m.FitStreamContext(context.Background(), docs)
+}
+
+// FitStreamContext is like FitStream, but checks ctx between documents and returns ctx.Err()
+// as soon as ctx is done, leaving the model unchanged. A call to Next blocking, e.g. on a channel,
+// is not interrupted: the iterator should return when ctx is done too.
+func (m *Model) FitStreamContext(ctx context.Context, docs TokenIterator) error {
+	for _, v := range []*TfIdfVectorizer{m.vectorizer, m.queryVectorizer} {
+		if v != nil && v.pivoted() && v.Pivot == 0 {
+			return errors.New("the corpus pivot cannot be learned when streaming: set it with WithPivot")
+		}
+	}
+
+	freq := m.newFrequencies()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		doc, err := docs.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		freq.add(doc)
+	}
+	if freq.nDocs == 0 {
+		return errors.New("empty corpus")
+	}
+	return m.fitFrequencies(ctx, freq.df, freq.cf, freq.nDocs, nil)
+}
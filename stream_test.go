@@ -0,0 +1,146 @@
+package tfidf
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rioloc/tfidf-go/token"
+)
+
+func TestModel_FitStream(t *testing.T) {
+	tokenizer := token.NewTokenizer(token.WithNormalizeFunc(strings.ToLower))
+	_, tokens, err := tokenizer.Tokenize(readmeDocuments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts []ModelOption
+		docs func() TokenIterator
+	}{
+		{
+			name: "iterator func",
+			docs: func() TokenIterator {
+				k := 0
+				return TokenIteratorFunc(func() ([]string, error) {
+					if k == len(tokens) {
+						return nil, io.EOF
+					}
+					k++
+					return tokens[k-1], nil
+				})
+			},
+		},
+		{
+			name: "channel with pruning",
+			opts: []ModelOption{WithMinDf(2), WithMaxFeatures(3), WithIdfScheme(PlainIdf)},
+			docs: func() TokenIterator {
+				ch := make(chan []string)
+				go func() {
+					defer close(ch)
+					for _, doc := range tokens {
+						ch <- doc
+					}
+				}()
+				return ChanIterator(ch)
+			},
+		},
+		{
+			name: "readers",
+			opts: []ModelOption{WithVectorizer(NewTfIdfVectorizer(WithNormLevel(PivotedNorm), WithPivot(0.2, 1)))},
+			docs: func() TokenIterator {
+				k := 0
+				return ReaderIterator(tokenizer, func() (io.Reader, error) {
+					if k == len(readmeDocuments) {
+						return nil, io.EOF
+					}
+					k++
+					return io.NopCloser(strings.NewReader(readmeDocuments[k-1])), nil
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := NewModel(tt.opts...)
+			wantMat, err := want.FitTransform(tokens)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			model := NewModel(tt.opts...)
+			if err := model.FitStream(tt.docs()); err != nil {
+				t.Fatalf("FitStream() error = %v", err)
+			}
+			if !slices.Equal(model.Vocabulary(), want.Vocabulary()) {
+				t.Errorf("Vocabulary() = %v, want %v", model.Vocabulary(), want.Vocabulary())
+			}
+			if !slices.Equal(model.PrunedTerms(), want.PrunedTerms()) {
+				t.Errorf("PrunedTerms() = %v, want %v", model.PrunedTerms(), want.PrunedTerms())
+			}
+			if !slices.Equal(model.DocumentFrequencies(), want.DocumentFrequencies()) {
+				t.Errorf("DocumentFrequencies() = %v, want %v", model.DocumentFrequencies(), want.DocumentFrequencies())
+			}
+			if model.NumDocuments() != len(tokens) {
+				t.Errorf("NumDocuments() = %d, want %d", model.NumDocuments(), len(tokens))
+			}
+			got, err := model.Transform(tokens)
+			if err != nil {
+				t.Fatalf("Transform() error = %v", err)
+			}
+			for i := range wantMat {
+				if !almostEqualSlices(got[i], wantMat[i], 1e-12) {
+					t.Errorf("Transform()[%d] = %v, want %v", i, got[i], wantMat[i])
+				}
+			}
+		})
+	}
+}
+
+func TestModel_FitStream_Errors(t *testing.T) {
+	errRead := errors.New("read failed")
+
+	tests := []struct {
+		name    string
+		model   *Model
+		docs    TokenIterator
+		wantErr error
+	}{
+		{
+			name:  "empty corpus",
+			model: NewModel(),
+			docs:  ChanIterator(func() chan []string { ch := make(chan []string); close(ch); return ch }()),
+		},
+		{
+			name:    "iterator error",
+			model:   NewModel(),
+			docs:    TokenIteratorFunc(func() ([]string, error) { return nil, errRead }),
+			wantErr: errRead,
+		},
+		{
+			name:  "pivot to learn",
+			model: NewModel(WithVectorizer(NewTfIdfVectorizer(WithNormLevel(PivotedUniqueNorm)))),
+			docs:  TokenIteratorFunc(func() ([]string, error) { return []string{"a"}, nil }),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.model.FitStream(tt.docs)
+			if err == nil {
+				t.Fatal("FitStream() expected error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("FitStream() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.model.Fitted() {
+				t.Error("model is fitted after a failed FitStream")
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package tfidf
+
+import (
+	"errors"
+	"math"
+)
+
+// TfScheme represents the weighting applied to raw term counts before they are multiplied by the IDF.
+// Terms absent from a document always keep a zero weight, whatever the scheme.
+type TfScheme int
+
+const (
+	// RawTf uses the raw count of the term in the document (default).
+	RawTf TfScheme = iota
+
+	// BinaryTf uses 1 for every term present in the document, regardless of its count.
+	// Equivalent to scikit-learn binary=True.
+	BinaryTf
+
+	// SublinearTf dampens repeated terms with 1 + log(tf).
+	// Equivalent to scikit-learn sublinear_tf=True, and to the "l" letter of the SMART notation.
+	SublinearTf
+
+	// LengthTf divides the count by the document length, i.e. its number of tokens,
+	// giving the relative frequency of the term in the document.
+	LengthTf
+
+	// AugmentedTf uses 0.5 + 0.5 * tf / max tf, where max tf is the highest count in the document.
+	// It prevents a bias towards longer documents. The "a" letter of the SMART notation.
+	AugmentedTf
+
+	// LogAverageTf uses (1 + log(tf)) / (1 + log(avg tf)), where avg tf is the average count
+	// of the terms present in the document. The "L" letter of the SMART notation.
+	LogAverageTf
+)
+
+// docStats holds the statistics of a whole document the LengthTf, AugmentedTf and LogAverageTf
+// schemes are based on. Counted from the tokens of the document, they include the terms missing
+// from the vocabulary, e.g. pruned terms or out-of-vocabulary query terms.
+type docStats struct {
+	length   float64 // Number of tokens
+	maxCount float64 // Highest count of a term
+	distinct float64 // Number of distinct terms
+}
+
+// add accounts for a term occurring count times in the document.
+func (d *docStats) add(count float64) {
+	d.length += count
+	d.maxCount = max(d.maxCount, count)
+	d.distinct++
+}
+
+// countStats returns the statistics of a document made of the given term counts only,
+// e.g. a row of a TF matrix whose document tokens are unknown. Zero counts are skipped.
+func countStats(counts []float64) docStats {
+	var d docStats
+	for _, count := range counts {
+		if count != 0 {
+			d.add(count)
+		}
+	}
+	return d
+}
+
+// WithTfScheme sets the weighting applied to raw term counts by the TF-IDF vectorizer.
+//
+// Parameters:
+//   - scheme: The TF scheme (RawTf, BinaryTf, SublinearTf, LengthTf, AugmentedTf or LogAverageTf)
+//
+// Example:
+//
+//	vectorizer := NewTfIdfVectorizer(WithTfScheme(SublinearTf)) // like sklearn sublinear_tf=True
+func WithTfScheme(scheme TfScheme) TfIdfOption {
+	return func(t *TfIdfVectorizer) {
+		t.TfScheme = scheme
+	}
+}
+
+// weight applies the TF scheme in place to the term counts of a document, whose statistics are doc.
+// Zero counts are skipped, so it can be applied both to a dense row and to
+// the stored values of a sparse row.
+func (s TfScheme) weight(counts []float64, doc docStats) error {
+	switch s {
+	case RawTf:
+		return nil
+	case BinaryTf:
+		for j, tf := range counts {
+			if tf != 0 {
+				counts[j] = 1
+			}
+		}
+	case SublinearTf:
+		for j, tf := range counts {
+			if tf != 0 {
+				counts[j] = 1 + math.Log(tf)
+			}
+		}
+	case LengthTf:
+		if doc.length == 0 {
+			return nil
+		}
+		for j := range counts {
+			counts[j] /= doc.length
+		}
+	case AugmentedTf:
+		for j, tf := range counts {
+			if tf != 0 {
+				counts[j] = 0.5 + 0.5*tf/doc.maxCount
+			}
+		}
+	case LogAverageTf:
+		if doc.distinct == 0 {
+			return nil
+		}
+		denominator := 1 + math.Log(doc.length/doc.distinct)
+		for j, tf := range counts {
+			if tf != 0 {
+				counts[j] = (1 + math.Log(tf)) / denominator
+			}
+		}
+	default:
+		return errors.New("invalid TF scheme")
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package tfidf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTfScheme_Weight(t *testing.T) {
+	counts := []float64{2, 0, 1, 4}
+	ln := math.Log
+
+	tests := []struct {
+		name   string
+		scheme TfScheme
+		want   []float64
+	}{
+		{name: "Raw", scheme: RawTf, want: []float64{2, 0, 1, 4}},
+		{name: "Binary", scheme: BinaryTf, want: []float64{1, 0, 1, 1}},
+		{name: "Sublinear", scheme: SublinearTf, want: []float64{1 + ln(2), 0, 1, 1 + ln(4)}},
+		{name: "Length", scheme: LengthTf, want: []float64{2.0 / 7, 0, 1.0 / 7, 4.0 / 7}},
+		{name: "Augmented", scheme: AugmentedTf, want: []float64{0.75, 0, 0.625, 1}},
+		{
+			name:   "Log average",
+			scheme: LogAverageTf,
+			want: []float64{
+				(1 + ln(2)) / (1 + ln(7.0/3)),
+				0,
+				1 / (1 + ln(7.0/3)),
+				(1 + ln(4)) / (1 + ln(7.0/3)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := append([]float64(nil), counts...)
+			if err := tt.scheme.weight(got, countStats(counts)); err != nil {
+				t.Fatalf("weight error: %v", err)
+			}
+			if !almostEqualSlices(got, tt.want, tol) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTfIdfVectorizer_TfScheme(t *testing.T) {
+	vocabulary := []string{"cat", "dog", "sat", "the"}
+	tokens := [][]string{
+		{"the", "cat", "sat", "the", "the"},
+		{"the", "dog", "dog"},
+		{},
+	}
+	idfVec := Idf(vocabulary, tokens, true)
+
+	for _, scheme := range []TfScheme{RawTf, BinaryTf, SublinearTf, LengthTf, AugmentedTf, LogAverageTf} {
+		v := NewTfIdfVectorizer(WithTfScheme(scheme))
+		tf := Tf(vocabulary, tokens)
+		dense, err := v.TfIdf(tf, idfVec)
+		if err != nil {
+			t.Fatalf("scheme %d: TfIdf error: %v", scheme, err)
+		}
+		if tf[0][3] != 3 {
+			t.Errorf("scheme %d: TF matrix modified", scheme)
+		}
+		sparse, err := v.TfIdfSparse(TfSparse(vocabulary, tokens), idfVec)
+		if err != nil {
+			t.Fatalf("scheme %d: TfIdfSparse error: %v", scheme, err)
+		}
+		got := sparse.Dense()
+		for i := range dense {
+			if !almostEqualSlices(got[i], dense[i], tol) {
+				t.Errorf("scheme %d, row %d: sparse %v, dense %v", scheme, i, got[i], dense[i])
+			}
+		}
+	}
+
+	// Sublinear TF without normalization, like sklearn sublinear_tf=True, norm=None
+	v := NewTfIdfVectorizer(WithTfScheme(SublinearTf), WithNormLevel(NoNorm))
+	got, err := v.TfIdf(Tf(vocabulary, tokens), idfVec)
+	if err != nil {
+		t.Fatalf("TfIdf error: %v", err)
+	}
+	if want := (1 + math.Log(3)) * idfVec[3]; math.Abs(got[0][3]-want) > tol {
+		t.Errorf("sublinear: got %v, want %v", got[0][3], want)
+	}
+
+	v = NewTfIdfVectorizer(WithTfScheme(TfScheme(99)))
+	if _, err := v.TfIdf(Tf(vocabulary, tokens), idfVec); err == nil {
+		t.Errorf("TfIdf() expected error for invalid TF scheme")
+	}
+	if _, err := v.TfIdfSparse(TfSparse(vocabulary, tokens), idfVec); err == nil {
+		t.Errorf("TfIdfSparse() expected error for invalid TF scheme")
+	}
+}
+
+func TestModel_TfSchemePruned(t *testing.T) {
+	// Only "a" is kept, but the document statistics still cover every token
+	docs := [][]string{{"a", "b", "c", "c"}, {"a", "d"}}
+	query := [][]string{{"a", "b", "c", "c"}, {"a", "z"}}
+	ln := math.Log
+
+	tests := []struct {
+		name   string
+		scheme TfScheme
+		want   []float64
+	}{
+		{name: "Length", scheme: LengthTf, want: []float64{0.25, 0.5}},
+		{name: "Augmented", scheme: AugmentedTf, want: []float64{0.75, 1}},
+		{name: "Log average", scheme: LogAverageTf, want: []float64{1 / (1 + ln(4.0/3)), 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewTfIdfVectorizer(WithTfScheme(tt.scheme), WithNormLevel(NoNorm))
+			m := NewModel(WithVectorizer(v), WithMinDf(2))
+			if err := m.Fit(docs); err != nil {
+				t.Fatalf("Fit error: %v", err)
+			}
+			if vocab := m.Vocabulary(); len(vocab) != 1 || vocab[0] != "a" {
+				t.Fatalf("vocabulary %v, want [a]", vocab)
+			}
+			got, err := m.Transform(query)
+			if err != nil {
+				t.Fatalf("Transform error: %v", err)
+			}
+			for i := range tt.want {
+				if math.Abs(got[i][0]-tt.want[i]) > tol {
+					t.Errorf("row %d: got %v, want %v", i, got[i][0], tt.want[i])
+				}
+			}
+		})
+	}
+}
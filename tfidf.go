@@ -2,7 +2,7 @@
 // computation for text analysis and document similarity calculations.
 //
 // This package implements the standard TF-IDF algorithm with support for different
-// normalization schemes (L1, L2, or no normalization) and optional smoothing for
+// normalization schemes (L1, L2, max, pivoted, or no normalization) and optional smoothing for
 // handling rare terms.
 //
 // Example usage:
@@ -23,8 +23,11 @@
 package tfidf
 
 import (
+	"context"
 	"errors"
 	"math"
+
+	"github.com/rioloc/tfidf-go/internal/parallel"
 )
 
 // NLevel represents the normalization level to apply to TF-IDF vectors.
@@ -45,6 +48,21 @@ const (
 	// This makes the Euclidean length of the vector equal to 1.
 	// Best choice for cosine similarity calculations (default).
 	L2Norm
+
+	// PivotedUniqueNorm divides the vector by (1 - Slope) * Pivot + Slope * u, where u is the number
+	// of distinct terms in the document and Pivot defaults to the average u across the corpus.
+	// It reduces the bias of cosine normalization against long documents.
+	// The "u" letter of the SMART notation.
+	PivotedUniqueNorm
+
+	// PivotedNorm divides the vector by (1 - Slope) * Pivot + Slope * ||v||, where ||v|| is its
+	// Euclidean norm and Pivot defaults to the average Euclidean norm across the corpus.
+	// Documents longer than the pivot are penalized less than with L2Norm, and shorter ones more.
+	PivotedNorm
+
+	// MaxNorm applies L∞ normalization, dividing by the largest absolute value,
+	// so that the heaviest term of each document weighs 1.
+	MaxNorm
 )
 
 // TfIdfVectorizer builds TF-IDF matrices from term frequency and inverse document frequency data.
@@ -53,13 +71,29 @@ type TfIdfVectorizer struct {
 	// NormLevel sets the normalization level to apply to each document vector.
 	// Defaults to L2Norm which is optimal for cosine similarity calculations.
 	NormLevel NLevel
+
+	// TfScheme sets the weighting applied to raw term counts before multiplying by the IDF.
+	// Defaults to RawTf.
+	TfScheme TfScheme
+
+	// Slope and Pivot parametrize the pivoted normalization levels.
+	// Slope defaults to 0.2. A zero Pivot is computed from the corpus: the average statistic
+	// of the normalization level across the documents being vectorized, or the fitted documents
+	// when used by a Model.
+	Slope float64
+	Pivot float64
+
+	// Workers sets the number of goroutines documents are sharded across when weighting and
+	// normalizing rows, and when counting terms in a Model. The output is identical whatever
+	// the number of workers. Defaults to 0, meaning GOMAXPROCS.
+	Workers int
 }
 
 // TfIdfOption is a functional option for configuring TfIdfVectorizer.
 type TfIdfOption func(*TfIdfVectorizer)
 
 // NewTfIdfVectorizer creates a new TF-IDF vectorizer with the specified options.
-// By default, it uses L2 normalization which is best for cosine similarity.
+// By default, it uses raw term counts and L2 normalization which is best for cosine similarity.
 //
 // Example:
 //
@@ -69,6 +103,7 @@ func NewTfIdfVectorizer(opts ...TfIdfOption) *TfIdfVectorizer {
 	t := &TfIdfVectorizer{
 		// Default to L2 normalization (best for cosine similarity)
 		NormLevel: L2Norm,
+		Slope:     0.2,
 	}
 	for _, opt := range opts {
 		opt(t)
@@ -79,7 +114,7 @@ func NewTfIdfVectorizer(opts ...TfIdfOption) *TfIdfVectorizer {
 // WithNormLevel sets the normalization level for the TF-IDF vectorizer.
 //
 // Parameters:
-//   - lvl: The normalization level (NoNorm, L1Norm, or L2Norm)
+//   - lvl: The normalization level (NoNorm, L1Norm, L2Norm, PivotedUniqueNorm, PivotedNorm or MaxNorm)
 //
 // Example:
 //
@@ -90,6 +125,31 @@ func WithNormLevel(lvl NLevel) TfIdfOption {
 	}
 }
 
+// WithPivot sets the slope and the pivot of the pivoted normalization levels.
+// A zero pivot is computed from the corpus.
+//
+// Example:
+//
+//	vectorizer := NewTfIdfVectorizer(WithNormLevel(PivotedUniqueNorm), WithPivot(0.25, 0))
+func WithPivot(slope, pivot float64) TfIdfOption {
+	return func(t *TfIdfVectorizer) {
+		t.Slope = slope
+		t.Pivot = pivot
+	}
+}
+
+// WithWorkers sets the number of goroutines the vectorizer shards documents across,
+// 1 to run sequentially. A number lower than 1 means GOMAXPROCS.
+//
+// Example:
+//
+//	model := NewModel(WithVectorizer(NewTfIdfVectorizer(WithWorkers(4))))
+func WithWorkers(n int) TfIdfOption {
+	return func(t *TfIdfVectorizer) {
+		t.Workers = n
+	}
+}
+
 // TfIdf computes the TF-IDF matrix by multiplying term frequency and inverse document frequency vectors.
 // The result is optionally normalized according to the vectorizer's NormLevel setting.
 //
@@ -101,9 +161,19 @@ func WithNormLevel(lvl NLevel) TfIdfOption {
 //   - tfIdfMat: TF-IDF matrix [documents][terms] with optional normalization applied
 //   - err: Error if normalization fails or input dimensions don't match
 //
-// The TF-IDF score for term j in document i is calculated as: tfVec[i][j] * idfVec[j]
+// The TF-IDF score for term j in document i is calculated as: tf(tfVec[i][j]) * idfVec[j],
+// where tf is the weighting selected by TfScheme (the raw count by default).
+// The document length, max and average counts some schemes use are those of the counts in tfVec[i]:
+// use TfSparse and TfIdfSparse to account for the tokens missing from the vocabulary.
 // After calculation, each document vector is normalized according to NormLevel.
+// tfVec is not modified.
 func (t *TfIdfVectorizer) TfIdf(tfVec [][]float64, idfVec []float64) (tfIdfMat [][]float64, err error) {
+	return t.TfIdfContext(context.Background(), tfVec, idfVec)
+}
+
+// TfIdfContext is like TfIdf, but checks ctx between documents and returns ctx.Err()
+// as soon as ctx is done.
+func (t *TfIdfVectorizer) TfIdfContext(ctx context.Context, tfVec [][]float64, idfVec []float64) (tfIdfMat [][]float64, err error) {
 	if len(tfVec) == 0 {
 		return nil, errors.New("empty TF matrix")
 	}
@@ -117,28 +187,49 @@ func (t *TfIdfVectorizer) TfIdf(tfVec [][]float64, idfVec []float64) (tfIdfMat [
 	}
 
 	// Calculate TF-IDF: tf[i][j] * idf[j] for each document i and term j
-	for i := range tfIdfMat {
-		for j := range tfIdfMat[i] {
-			tfIdfMat[i][j] = tfVec[i][j] * idfVec[j]
-		}
-		// Apply normalization to make documents comparable regardless of length
-		tfIdfMat[i], err = t.doNormalize(tfIdfMat[i])
-		if err != nil {
-			return nil, err
+	err = parallel.For(t.Workers, len(tfIdfMat), func(_, lo, hi int) error {
+		for i := lo; i < hi; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			copy(tfIdfMat[i], tfVec[i])
+			if err := t.TfScheme.weight(tfIdfMat[i], countStats(tfVec[i])); err != nil {
+				return err
+			}
+			for j := range tfIdfMat[i] {
+				tfIdfMat[i][j] *= idfVec[j]
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply normalization to make documents comparable regardless of length
+	norm, err := t.withCorpusPivot(ctx, tfIdfMat, tfVec)
+	if err != nil {
+		return nil, err
+	}
+	if err := norm.normalizeRows(ctx, tfIdfMat, tfVec); err != nil {
+		return nil, err
 	}
 
 	return tfIdfMat, nil
 }
 
-// doNormalize applies the specified normalization to a document vector.
+// doNormalize applies the specified normalization to a document vector, whose term counts are tf.
 // This makes documents comparable regardless of their length or absolute TF-IDF scale.
-func (t *TfIdfVectorizer) doNormalize(vec []float64) ([]float64, error) {
+func (t *TfIdfVectorizer) doNormalize(vec, tf []float64) ([]float64, error) {
 	switch t.NormLevel {
 	case L1Norm:
 		return l1Normalize(vec), nil
 	case L2Norm:
 		return l2Normalize(vec), nil
+	case PivotedUniqueNorm, PivotedNorm:
+		return pivotedNormalize(vec, t.Slope, t.Pivot, t.pivotStatistic(vec, tf)), nil
+	case MaxNorm:
+		return maxNormalize(vec), nil
 	case NoNorm:
 		return vec, nil
 	default:
@@ -146,6 +237,86 @@ func (t *TfIdfVectorizer) doNormalize(vec []float64) ([]float64, error) {
 	}
 }
 
+// normalizeRows normalizes each document vector in place, sharding documents across workers.
+// tf holds the term counts of each document, aligned with its vector.
+// It returns ctx.Err() if ctx is done before all documents are normalized.
+func (t *TfIdfVectorizer) normalizeRows(ctx context.Context, docs, tf [][]float64) error {
+	return parallel.For(t.Workers, len(docs), func(_, lo, hi int) error {
+		for i := lo; i < hi; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if _, err := t.doNormalize(docs[i], tf[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// pivoted reports whether the normalization level depends on a pivot.
+func (t *TfIdfVectorizer) pivoted() bool {
+	return t.NormLevel == PivotedUniqueNorm || t.NormLevel == PivotedNorm
+}
+
+// pivotStatistic returns the document statistic a pivoted normalization level is based on:
+// the number of distinct terms, i.e. of non-zero term counts in tf, for PivotedUniqueNorm,
+// and the Euclidean norm of the weighted vector for PivotedNorm.
+// Counting terms before weighting keeps the terms whose IDF is zero.
+func (t *TfIdfVectorizer) pivotStatistic(vec, tf []float64) float64 {
+	if t.NormLevel == PivotedNorm {
+		var norm float64
+		for _, val := range vec {
+			norm += val * val
+		}
+		return math.Sqrt(norm)
+	}
+	var unique float64
+	for _, val := range tf {
+		if val != 0 {
+			unique++
+		}
+	}
+	return unique
+}
+
+// withCorpusPivot returns the vectorizer to normalize the given weighted document vectors with,
+// whose term counts are tf. If the normalization level is pivoted and no pivot is set, it returns
+// a copy of the vectorizer whose pivot is the average statistic across the documents;
+// otherwise it returns t itself.
+// It returns ctx.Err() if ctx is done before the statistics of all documents are computed.
+func (t *TfIdfVectorizer) withCorpusPivot(ctx context.Context, docs, tf [][]float64) (*TfIdfVectorizer, error) {
+	if !t.pivoted() || t.Pivot != 0 || len(docs) == 0 {
+		return t, nil
+	}
+	var sum float64
+	for i, vec := range docs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sum += t.pivotStatistic(vec, tf[i])
+	}
+	fitted := *t
+	fitted.Pivot = sum / float64(len(docs))
+	return &fitted, nil
+}
+
+// pivotedNormalize divides the vector by the pivoted normalization factor
+// (1 - slope) * pivot + slope * stat, where stat is the statistic of the document
+// the pivot is an average of.
+//
+// Returns the original vector unchanged if the factor is zero.
+func pivotedNormalize(vec []float64, slope, pivot, stat float64) []float64 {
+	factor := (1-slope)*pivot + slope*stat
+	if factor == 0 {
+		return vec
+	}
+	for i := range vec {
+		vec[i] /= factor
+	}
+	return vec
+}
+
 // l1Normalize scales the vector so that the sum of the absolute values of its components equals 1.
 // This creates a probability-like distribution where all values sum to 1.
 //
@@ -166,6 +337,25 @@ func l1Normalize(vec []float64) []float64 {
 	return vec
 }
 
+// maxNormalize scales the vector so that its largest absolute component equals 1.
+//
+// Formula: v_normalized[i] = v[i] / max(|v[0]|, |v[1]|, ..., |v[n]|)
+//
+// Returns the original vector unchanged if all elements are zero.
+func maxNormalize(vec []float64) []float64 {
+	var norm float64
+	for _, val := range vec {
+		norm = max(norm, math.Abs(val))
+	}
+	if norm == 0 {
+		return vec // Return unchanged if vector is all zeros
+	}
+	for i := range vec {
+		vec[i] /= norm
+	}
+	return vec
+}
+
 // l2Normalize scales the TF-IDF vector so its Euclidean length equals 1.
 // This normalization ensures that document similarity comparisons are fair and consistent,
 // focusing on term usage patterns rather than document length or absolute TF-IDF scale.
@@ -202,8 +392,8 @@ func l2Normalize(vec []float64) []float64 {
 //   - Term frequency matrix [documents][terms] where element [i][j] represents
 //     the count of vocabulary[j] in document i
 //
-// The raw term counts are returned without any normalization. Common TF normalizations
-// like tf[i][j] / |document_i| can be applied separately if needed.
+// The raw term counts are returned without any normalization. TF weightings like
+// binary, sublinear or length-normalized counts are applied by the vectorizer, see TfScheme.
 //
 // Example:
 //
@@ -213,30 +403,58 @@ func l2Normalize(vec []float64) []float64 {
 //	// tfMatrix[0] = [1, 1, 1] (document 0: "the"=1, "cat"=1, "sat"=1)
 //	// tfMatrix[1] = [2, 1, 1] (document 1: "the"=2, "cat"=1, "sat"=1)
 func Tf(vocabulary []string, tokens [][]string) [][]float64 {
+	// The background context is never done, so no error can occur
+	tf, _ := countTf(context.Background(), vocabulary, tokens, 0)
+	return tf
+}
+
+// Tf is like the package-level Tf, but shards documents across the vectorizer's Workers.
+func (t *TfIdfVectorizer) Tf(vocabulary []string, tokens [][]string) [][]float64 {
+	tf, _ := countTf(context.Background(), vocabulary, tokens, t.Workers)
+	return tf
+}
+
+// TfContext is like Tf, but checks ctx between documents and returns ctx.Err() as soon as ctx is done.
+func (t *TfIdfVectorizer) TfContext(ctx context.Context, vocabulary []string, tokens [][]string) ([][]float64, error) {
+	return countTf(ctx, vocabulary, tokens, t.Workers)
+}
+
+// countTf computes the term count matrix, sharding documents across workers.
+// It returns ctx.Err() if ctx is done before all documents are counted.
+func countTf(ctx context.Context, vocabulary []string, tokens [][]string, workers int) ([][]float64, error) {
 	// Initialize matrix: [num_documents][num_terms]
 	termsCountMatrix := make([][]float64, len(tokens))
 	for i := range termsCountMatrix {
 		termsCountMatrix[i] = make([]float64, len(vocabulary))
 	}
 
-	// Count term frequencies for each document
-	for i, tokensRow := range tokens {
-		// Build frequency map for this document
-		termsMap := make(map[string]int)
-		for _, term := range tokensRow {
-			termsMap[term]++
-		}
+	// Count term frequencies for each document, sharding documents across workers
+	err := parallel.For(workers, len(tokens), func(_, lo, hi int) error {
+		for i := lo; i < hi; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			// Build frequency map for this document
+			termsMap := make(map[string]int)
+			for _, term := range tokens[i] {
+				termsMap[term]++
+			}
 
-		// Fill matrix row with term counts
-		for j, token := range vocabulary {
-			if val, found := termsMap[token]; found {
-				termsCountMatrix[i][j] = float64(val)
+			// Fill matrix row with term counts
+			for j, token := range vocabulary {
+				if val, found := termsMap[token]; found {
+					termsCountMatrix[i][j] = float64(val)
+				}
+				// Note: missing terms remain 0 (default value)
 			}
-			// Note: missing terms remain 0 (default value)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return termsCountMatrix
+	return termsCountMatrix, nil
 }
 
 // Idf calculates the Inverse Document Frequency vector for a vocabulary across a document corpus.
@@ -258,9 +476,10 @@ func Tf(vocabulary []string, tokens [][]string) [][]float64 {
 //
 // The +1 constant is added to ensure all IDF values are positive.
 // Smoothing is recommended to handle rare terms and prevent extreme IDF values.
+// For other formulas, see IdfScheme and IdfFromDf.
 //
-// Time Complexity: O(total_tokens + vocabulary_size × num_documents)
-// Space Complexity: O(total_unique_tokens) for document maps
+// Time Complexity: O(total_tokens + vocabulary_size)
+// Space Complexity: O(vocabulary_size)
 //
 // Example:
 //
@@ -270,52 +489,127 @@ func Tf(vocabulary []string, tokens [][]string) [][]float64 {
 //	// "the" appears in 2/3 documents (common) -> lower IDF
 //	// "rare" appears in 1/3 documents (rare) -> higher IDF
 func Idf(vocabulary []string, tokens [][]string, smoothing bool) []float64 {
-	idfVec := make([]float64, len(vocabulary))
+	// The background context is never done, so no error can occur
+	idfVec, _ := computeIdf(context.Background(), vocabulary, tokens, smoothing, 0)
+	return idfVec
+}
+
+// Idf is like the package-level Idf, but shards documents across the vectorizer's Workers.
+func (t *TfIdfVectorizer) Idf(vocabulary []string, tokens [][]string, smoothing bool) []float64 {
+	idfVec, _ := computeIdf(context.Background(), vocabulary, tokens, smoothing, t.Workers)
+	return idfVec
+}
+
+// IdfContext is like Idf, but checks ctx between documents and returns ctx.Err() as soon as ctx is done.
+func (t *TfIdfVectorizer) IdfContext(ctx context.Context, vocabulary []string, tokens [][]string, smoothing bool) ([]float64, error) {
+	return computeIdf(ctx, vocabulary, tokens, smoothing, t.Workers)
+}
+
+// computeIdf computes the IDF vector, counting document frequencies across workers.
+// It returns ctx.Err() if ctx is done before all documents are counted.
+func computeIdf(ctx context.Context, vocabulary []string, tokens [][]string, smoothing bool, workers int) ([]float64, error) {
 	total := len(tokens)
 
 	if total == 0 {
 		// Handle edge case: no documents
+		idfVec := make([]float64, len(vocabulary))
 		for i := range idfVec {
 			idfVec[i] = 1.0 // Default IDF value
 		}
-		return idfVec
+		return idfVec, nil
 	}
 
-	// Pre-compute document maps for O(1) term lookup instead of O(doc_length)
-	// This optimization converts O(vocabulary × documents × avg_doc_length) 
-	// to O(total_tokens + vocabulary × documents)
-	docMaps := make([]map[string]struct{}, len(tokens))
-	for i, doc := range tokens {
-		docMaps[i] = make(map[string]struct{})
-		for _, token := range doc {
-			docMaps[i][token] = struct{}{}
-		}
+	scheme := StandardIdf
+	if smoothing {
+		scheme = SmoothIdf
+	}
+	dfVec, err := countDf(ctx, vocabulary, tokens, workers)
+	if err != nil {
+		return nil, err
 	}
+	// Both schemes are valid, so no error can occur
+	idfVec, _ := IdfFromDf(dfVec, total, scheme)
+	return idfVec, nil
+}
 
-	// Calculate IDF for each term in vocabulary
+// Df calculates the Document Frequency vector for a vocabulary across a document corpus.
+// Document frequency is the number of documents in which each term appears at least once.
+//
+// Parameters:
+//   - vocabulary: Ordered list of unique terms to calculate document frequencies for
+//   - tokens: Tokenized documents where tokens[i] contains all tokens for document i
+//
+// Returns:
+//   - Document frequency vector [terms] where element [j] is the number of documents containing vocabulary[j]
+//
+// Example:
+//
+//	vocabulary := []string{"the", "cat", "rare"}
+//	tokens := [][]string{{"the", "cat"}, {"the", "dog"}, {"cat", "rare"}}
+//	dfVec := Df(vocabulary, tokens)
+//	// dfVec = [2, 2, 1]
+func Df(vocabulary []string, tokens [][]string) []int {
+	// The background context is never done, so no error can occur
+	dfVec, _ := countDf(context.Background(), vocabulary, tokens, 0)
+	return dfVec
+}
+
+// Df is like the package-level Df, but shards documents across the vectorizer's Workers.
+func (t *TfIdfVectorizer) Df(vocabulary []string, tokens [][]string) []int {
+	dfVec, _ := countDf(context.Background(), vocabulary, tokens, t.Workers)
+	return dfVec
+}
+
+// DfContext is like Df, but checks ctx between documents and returns ctx.Err() as soon as ctx is done.
+func (t *TfIdfVectorizer) DfContext(ctx context.Context, vocabulary []string, tokens [][]string) ([]int, error) {
+	return countDf(ctx, vocabulary, tokens, t.Workers)
+}
+
+// countDf computes the document frequency vector, sharding documents across workers.
+// It returns ctx.Err() if ctx is done before all documents are counted.
+func countDf(ctx context.Context, vocabulary []string, tokens [][]string, workers int) ([]int, error) {
+	// Map each term to its position so that every token is looked up in O(1)
+	index := make(map[string]int, len(vocabulary))
 	for j, term := range vocabulary {
-		docCount := 0
-		// Count documents containing this term
-		for _, docMap := range docMaps {
-			if _, found := docMap[term]; found {
-				docCount++
+		index[term] = j
+	}
+
+	// Count each shard of documents separately, only for the terms it contains,
+	// then sum the shard counts
+	type termCount struct {
+		df       int // Number of documents of the shard containing the term
+		lastSeen int // Last document (+1) in which the term was counted
+	}
+	shards := make([]map[int]termCount, parallel.Workers(workers, len(tokens)))
+	err := parallel.For(workers, len(tokens), func(shard, lo, hi int) error {
+		counts := make(map[int]termCount)
+		for i := lo; i < hi; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			for _, token := range tokens[i] {
+				j, found := index[token]
+				if !found {
+					continue
+				}
+				// Repeated occurrences within the same document are counted once
+				if c := counts[j]; c.lastSeen != i+1 {
+					counts[j] = termCount{df: c.df + 1, lastSeen: i + 1}
+				}
 			}
 		}
+		shards[shard] = counts
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		if smoothing {
-			// Add-one smoothing: prevents log(0) and reduces impact of very rare terms
-			idfVec[j] = math.Log(float64(total+1)/float64(docCount+1)) + 1
-			continue
+	dfVec := make([]int, len(vocabulary))
+	for _, counts := range shards {
+		for j, c := range counts {
+			dfVec[j] += c.df
 		}
-		if docCount == 0 {
-			// Handle terms not found in any document (shouldn't happen with proper vocabulary)
-			// Assign high IDF score as these terms are extremely rare
-			idfVec[j] = math.Log(float64(total)) + 1
-			continue
-		}
-		// Standard IDF formula
-		idfVec[j] = math.Log(float64(total)/float64(docCount)) + 1
 	}
-
-	return idfVec
+	return dfVec, nil
 }
@@ -1,7 +1,10 @@
 package tfidf
 
 import (
+	"context"
+	"errors"
 	"math"
+	"reflect"
 	"testing"
 )
 
@@ -116,6 +119,22 @@ func TestIdf(t *testing.T) {
 	}
 }
 
+func TestDf(t *testing.T) {
+	vocab := []string{"the", "cat", "rare", "missing"}
+	tokens := [][]string{
+		{"the", "cat", "the"},
+		{"the", "dog"},
+		{"cat", "rare"},
+	}
+	want := []int{2, 2, 1, 0}
+	got := Df(vocab, tokens)
+	for j := range want {
+		if got[j] != want[j] {
+			t.Errorf("term %q: got %d, want %d", vocab[j], got[j], want[j])
+		}
+	}
+}
+
 func TestTfIdfVectorizer_TfIdf(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -165,3 +184,125 @@ func TestTfIdfVectorizer_TfIdf(t *testing.T) {
 		})
 	}
 }
+
+func TestPivotedNorm_MaxNorm(t *testing.T) {
+	vocabulary := []string{"a", "b", "c"}
+	tokens := [][]string{
+		{"a", "a", "a", "b", "b", "b", "b"}, // norm 5
+		{"c"},                               // norm 1
+	}
+	ones := []float64{1, 1, 1}
+
+	// Average norm: 3, slope 0.2: factors 0.8*3+0.2*5 = 3.4 and 0.8*3+0.2*1 = 2.6
+	tests := []struct {
+		name string
+		v    *TfIdfVectorizer
+		want [][]float64
+	}{
+		{
+			name: "Pivoted",
+			v:    NewTfIdfVectorizer(WithNormLevel(PivotedNorm)),
+			want: [][]float64{{3 / 3.4, 4 / 3.4, 0}, {0, 0, 1 / 2.6}},
+		},
+		{
+			name: "Pivoted fixed pivot",
+			v:    NewTfIdfVectorizer(WithNormLevel(PivotedNorm), WithPivot(0.5, 1)),
+			want: [][]float64{{3 / 3.0, 4 / 3.0, 0}, {0, 0, 1}},
+		},
+		{
+			name: "Max",
+			v:    NewTfIdfVectorizer(WithNormLevel(MaxNorm)),
+			want: [][]float64{{0.75, 1, 0}, {0, 0, 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.v.TfIdf(Tf(vocabulary, tokens), ones)
+			if err != nil {
+				t.Fatalf("TfIdf error: %v", err)
+			}
+			sparse, err := tt.v.TfIdfSparse(TfSparse(vocabulary, tokens), ones)
+			if err != nil {
+				t.Fatalf("TfIdfSparse error: %v", err)
+			}
+			for i, row := range sparse.Dense() {
+				if !almostEqualSlices(got[i], tt.want[i], tol) {
+					t.Errorf("row %d: got %v, want %v", i, got[i], tt.want[i])
+				}
+				if !almostEqualSlices(row, tt.want[i], tol) {
+					t.Errorf("sparse row %d: got %v, want %v", i, row, tt.want[i])
+				}
+			}
+		})
+	}
+
+	// A model keeps the average norm of the fitted corpus for new documents
+	m := NewModel(WithVectorizer(NewTfIdfVectorizer(WithNormLevel(PivotedNorm))), WithIdfScheme(NoIdf))
+	if err := m.Fit(tokens); err != nil {
+		t.Fatalf("Fit error: %v", err)
+	}
+	got, err := m.Transform([][]string{{"c", "c"}})
+	if err != nil {
+		t.Fatalf("Transform error: %v", err)
+	}
+	if want := 2 / (0.8*3 + 0.2*2); math.Abs(got[0][2]-want) > tol {
+		t.Errorf("fitted pivot: got %v, want %v", got[0][2], want)
+	}
+}
+
+func TestTfIdfVectorizer_Context(t *testing.T) {
+	vocabulary := []string{"big", "brother", "watching"}
+	tokens := [][]string{{"big", "brother"}, {"brother", "is", "watching"}, {"big", "big"}}
+	v := NewTfIdfVectorizer(WithWorkers(2), WithNormLevel(PivotedNorm))
+	dense, err := v.TfIdf(Tf(vocabulary, tokens), Idf(vocabulary, tokens, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sparse, err := v.TfIdfSparse(TfSparse(vocabulary, tokens), Idf(vocabulary, tokens, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context) (any, error)
+		want any
+	}{
+		{name: "TfContext", call: func(ctx context.Context) (any, error) {
+			return v.TfContext(ctx, vocabulary, tokens)
+		}, want: v.Tf(vocabulary, tokens)},
+		{name: "TfSparseContext", call: func(ctx context.Context) (any, error) {
+			return v.TfSparseContext(ctx, vocabulary, tokens)
+		}, want: v.TfSparse(vocabulary, tokens)},
+		{name: "DfContext", call: func(ctx context.Context) (any, error) {
+			return v.DfContext(ctx, vocabulary, tokens)
+		}, want: v.Df(vocabulary, tokens)},
+		{name: "IdfContext", call: func(ctx context.Context) (any, error) {
+			return v.IdfContext(ctx, vocabulary, tokens, true)
+		}, want: v.Idf(vocabulary, tokens, true)},
+		{name: "TfIdfContext", call: func(ctx context.Context) (any, error) {
+			return v.TfIdfContext(ctx, Tf(vocabulary, tokens), Idf(vocabulary, tokens, true))
+		}, want: dense},
+		{name: "TfIdfSparseContext", call: func(ctx context.Context) (any, error) {
+			return v.TfIdfSparseContext(ctx, TfSparse(vocabulary, tokens), Idf(vocabulary, tokens, true))
+		}, want: sparse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.call(context.Background())
+			if err != nil {
+				t.Fatalf("%s() error = %v", tt.name, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s() = %v, want %v", tt.name, got, tt.want)
+			}
+			if _, err := tt.call(canceled); !errors.Is(err, context.Canceled) {
+				t.Errorf("%s() error = %v, want %v", tt.name, err, context.Canceled)
+			}
+		})
+	}
+}
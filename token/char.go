@@ -0,0 +1,188 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/rioloc/tfidf-go/internal/parallel"
+)
+
+// whiteSpaces matches the runs of white space replaced with a single space by the Char mode,
+// like scikit-learn does: a single white space character, e.g. a newline, is kept as is.
+var whiteSpaces = regexp.MustCompile(`\s\s+`)
+
+// CharMode selects how character n-grams are extracted from a document.
+type CharMode int
+
+const (
+	// Char extracts n-grams over the whole document, spanning across words.
+	// Runs of two or more white space characters are replaced with a single space first,
+	// like scikit-learn does, keeping the leading and trailing space of the document.
+	Char CharMode = iota
+
+	// CharWB extracts n-grams only from inside words, padding each word with a space
+	// on both sides, so that n-grams at word edges are distinguishable.
+	CharWB
+)
+
+// CharNGramTokenizer is a tokenizer emitting character n-grams instead of words.
+// Character n-grams are robust to typos and inflections: "learning" and "lerning"
+// still share most of their n-grams.
+// It satisfies the same Tokenize contract as Tokenizer, so it can be used wherever
+// a Tokenizer is, like in the similarity package.
+type CharNGramTokenizer struct {
+	mode          CharMode            // How n-grams are extracted from a document.
+	ngramMin      int                 // Minimum number of runes in an n-gram.
+	ngramMax      int                 // Maximum number of runes in an n-gram.
+	normalizeFunc func(string) string // An optional function to normalize documents (e.g., convert to lowercase).
+	workers       int                 // Number of goroutines tokenizing documents, 0 for GOMAXPROCS.
+}
+
+// CharNGramOption is a function type that allows for configuring the CharNGramTokenizer.
+type CharNGramOption func(*CharNGramTokenizer)
+
+// WithCharMode is a functional option to set the extraction mode. Defaults to Char.
+func WithCharMode(mode CharMode) CharNGramOption {
+	return func(t *CharNGramTokenizer) {
+		t.mode = mode
+	}
+}
+
+// WithCharNGramRange is a functional option to emit n-grams with a number of runes
+// between min and max included. Defaults to (3, 3).
+func WithCharNGramRange(min, max int) CharNGramOption {
+	return func(t *CharNGramTokenizer) {
+		t.ngramMin = min
+		t.ngramMax = max
+	}
+}
+
+// WithCharNormalizeFunc is a functional option to set a normalization function,
+// applied to each whole document before extracting n-grams.
+func WithCharNormalizeFunc(fn func(string) string) CharNGramOption {
+	return func(t *CharNGramTokenizer) {
+		t.normalizeFunc = fn
+	}
+}
+
+// WithCharWorkers is a functional option to set the number of goroutines Tokenize shards documents across.
+// The output is identical whatever the number of workers. Defaults to GOMAXPROCS; with more than one worker,
+// the normalization function must be safe for concurrent use.
+func WithCharWorkers(n int) CharNGramOption {
+	return func(t *CharNGramTokenizer) {
+		t.workers = n
+	}
+}
+
+// NewCharNGramTokenizer is a constructor function that creates and returns a new CharNGramTokenizer instance.
+// It accepts a variable number of CharNGramOption functions to configure the tokenizer.
+func NewCharNGramTokenizer(opts ...CharNGramOption) *CharNGramTokenizer {
+	t := &CharNGramTokenizer{
+		mode:     Char,
+		ngramMin: 3,
+		ngramMax: 3,
+	}
+
+	// Apply all provided options to the tokenizer.
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Tokenize takes a slice of documents and returns a vocabulary (unique n-grams)
+// and a 2D slice representing the n-grams for each document.
+func (t *CharNGramTokenizer) Tokenize(documents []string) ([]string, [][]string, error) {
+	return t.TokenizeContext(context.Background(), documents)
+}
+
+// TokenizeContext is like Tokenize, but checks ctx between documents and returns ctx.Err()
+// as soon as ctx is done.
+func (t *CharNGramTokenizer) TokenizeContext(ctx context.Context, documents []string) ([]string, [][]string, error) {
+	if t.ngramMin < 1 || t.ngramMax < t.ngramMin {
+		return nil, nil, errors.New("invalid n-gram range")
+	}
+
+	tokens := make([][]string, len(documents))
+	err := parallel.For(t.workers, len(documents), func(_, lo, hi int) error {
+		for i := lo; i < hi; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			tokens[i] = t.tokenizeDocument(documents[i])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return vocabulary(tokens), tokens, nil
+}
+
+// TokenizeReader reads a single document from r and returns its n-grams, like Tokenize does.
+func (t *CharNGramTokenizer) TokenizeReader(r io.Reader) ([]string, error) {
+	if t.ngramMin < 1 || t.ngramMax < t.ngramMin {
+		return nil, errors.New("invalid n-gram range")
+	}
+	doc, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return t.tokenizeDocument(string(doc)), nil
+}
+
+// tokenizeDocument returns the n-grams of a single document.
+func (t *CharNGramTokenizer) tokenizeDocument(doc string) []string {
+	if t.normalizeFunc != nil {
+		doc = t.normalizeFunc(doc)
+	}
+	if t.mode == CharWB {
+		return t.charWBNGrams(doc)
+	}
+	return t.charNGrams(doc)
+}
+
+// charNGrams extracts the n-grams of a whole document, grouped by increasing n.
+// Example, with range (3, 3):
+// Input: "to be"
+// Returns: ["to ", "o b", " be"]
+func (t *CharNGramTokenizer) charNGrams(doc string) []string {
+	// Collapse white space runs, so that layout does not produce distinct n-grams.
+	runes := []rune(whiteSpaces.ReplaceAllString(doc, " "))
+
+	var grams []string
+	for n := t.ngramMin; n <= t.ngramMax; n++ {
+		for i := 0; i+n <= len(runes); i++ {
+			grams = append(grams, string(runes[i:i+n]))
+		}
+	}
+	return grams
+}
+
+// charWBNGrams extracts the n-grams of each word padded with spaces, grouped by word.
+// A padded word not longer than n is emitted whole, once.
+// Example, with range (3, 3):
+// Input: "to be"
+// Returns: [" to", "to ", " be", "be "]
+func (t *CharNGramTokenizer) charWBNGrams(doc string) []string {
+	var grams []string
+	for _, word := range strings.Fields(doc) {
+		runes := []rune(" " + word + " ")
+		for n := t.ngramMin; n <= t.ngramMax; n++ {
+			if n >= len(runes) {
+				// The padded word fits in a single n-gram: emit it whole once, and stop here
+				// since every larger n would produce the same n-gram.
+				grams = append(grams, string(runes))
+				break
+			}
+			for i := 0; i+n <= len(runes); i++ {
+				grams = append(grams, string(runes[i:i+n]))
+			}
+		}
+	}
+	return grams
+}
@@ -0,0 +1,113 @@
+package token
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCharNGramTokenizer_Tokenize(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []CharNGramOption
+		doc      string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "Char trigrams",
+			doc:      "to  be",
+			expected: []string{"to ", "o b", " be"},
+		},
+		{
+			name:     "Char document edges",
+			doc:      " ab ",
+			expected: []string{" ab", "ab "},
+		},
+		{
+			name:     "Char single white space kept",
+			doc:      "a\nb",
+			expected: []string{"a\nb"},
+		},
+		{
+			name:     "Char range with normalization",
+			opts:     []CharNGramOption{WithCharNGramRange(1, 2), WithCharNormalizeFunc(strings.ToLower)},
+			doc:      "AbC",
+			expected: []string{"a", "b", "c", "ab", "bc"},
+		},
+		{
+			name:     "Char multibyte runes",
+			opts:     []CharNGramOption{WithCharNGramRange(2, 2)},
+			doc:      "café",
+			expected: []string{"ca", "af", "fé"},
+		},
+		{
+			name:     "Word boundaries",
+			opts:     []CharNGramOption{WithCharMode(CharWB)},
+			doc:      "to be",
+			expected: []string{" to", "to ", " be", "be "},
+		},
+		{
+			name:     "Word boundaries with document edges",
+			opts:     []CharNGramOption{WithCharMode(CharWB)},
+			doc:      " ab ",
+			expected: []string{" ab", "ab "},
+		},
+		{
+			name:     "Word boundaries across a newline",
+			opts:     []CharNGramOption{WithCharMode(CharWB)},
+			doc:      "a\nb",
+			expected: []string{" a ", " b "},
+		},
+		{
+			name:     "Word boundaries with short words",
+			opts:     []CharNGramOption{WithCharMode(CharWB), WithCharNGramRange(2, 4)},
+			doc:      "a cat",
+			expected: []string{" a", "a ", " a ", " c", "ca", "at", "t ", " ca", "cat", "at ", " cat", "cat "},
+		},
+		{
+			name:    "Invalid range",
+			opts:    []CharNGramOption{WithCharNGramRange(0, 2)},
+			doc:     "to be",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, tokens, err := NewCharNGramTokenizer(tt.opts...).Tokenize([]string{tt.doc})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Tokenize() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Tokenize error: %v", err)
+			}
+			if !slices.Equal(tokens[0], tt.expected) {
+				t.Errorf("got %q, want %q", tokens[0], tt.expected)
+			}
+		})
+	}
+}
+
+func TestCharNGramTokenizer_Typos(t *testing.T) {
+	// Documents with a typo still share most of their n-grams.
+	vocab, tokens, err := NewCharNGramTokenizer(WithCharMode(CharWB)).Tokenize([]string{"learning", "lerning"})
+	if err != nil {
+		t.Fatalf("Tokenize error: %v", err)
+	}
+	shared := 0
+	for _, gram := range tokens[1] {
+		if slices.Contains(tokens[0], gram) {
+			shared++
+		}
+	}
+	if shared < len(tokens[1])/2 {
+		t.Errorf("only %d of %d n-grams shared", shared, len(tokens[1]))
+	}
+	if !slices.IsSorted(vocab) {
+		t.Errorf("vocabulary is not sorted: %v", vocab)
+	}
+}
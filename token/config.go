@@ -0,0 +1,306 @@
+package token
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// The tokenizers implement json.Marshaler and json.Unmarshaler, so that their configuration
+// can be persisted together with a fitted model and restored identically.
+// Functions cannot be serialized in general: only the normalization functions listed in
+// namedFuncs and the built-in stemmers are recognized, and marshaling a tokenizer using
+// any other function, or a FilterFunc, fails.
+// The number of workers is a runtime setting: it is not serialized, and UnmarshalJSON keeps it.
+
+// namedFuncs lists the normalization functions that can be serialized, by name.
+var namedFuncs = map[string]func(string) string{
+	"lowercase": strings.ToLower,
+	"uppercase": strings.ToUpper,
+}
+
+// builtinStemmers lists the stemmers that can be serialized, by language.
+var builtinStemmers = map[Language]Stemmer{
+	English: StemEnglish,
+	German:  StemGerman,
+	Italian: StemItalian,
+	Spanish: StemSpanish,
+}
+
+var (
+	tokenClassNames = map[TokenClass]string{
+		Letters: "letters", Alphanumeric: "alphanumeric", Words: "words", NonSpace: "nonspace",
+	}
+	charModeNames = map[CharMode]string{
+		Char: "char", CharWB: "char_wb",
+	}
+)
+
+// tokenizerConfig is the serialized form of a Tokenizer.
+type tokenizerConfig struct {
+	Class     string         `json:"class"`
+	Pattern   string         `json:"pattern,omitempty"`
+	MinLength int            `json:"min_length"`
+	MaxLength int            `json:"max_length"`
+	NGramMin  int            `json:"ngram_min"`
+	NGramMax  int            `json:"ngram_max"`
+	NGramSep  string         `json:"ngram_separator"`
+	Normalize string         `json:"normalize,omitempty"`
+	Filters   []filterConfig `json:"filters,omitempty"`
+}
+
+// filterConfig is the serialized form of a built-in Filter.
+type filterConfig struct {
+	Type     string              `json:"type"`
+	Func     string              `json:"func,omitempty"`
+	Words    []string            `json:"words,omitempty"`
+	Language Language            `json:"language,omitempty"`
+	Min      int                 `json:"min,omitempty"`
+	Max      int                 `json:"max,omitempty"`
+	Synonyms map[string][]string `json:"synonyms,omitempty"`
+}
+
+// charNGramConfig is the serialized form of a CharNGramTokenizer.
+type charNGramConfig struct {
+	Mode      string `json:"mode"`
+	NGramMin  int    `json:"ngram_min"`
+	NGramMax  int    `json:"ngram_max"`
+	Normalize string `json:"normalize,omitempty"`
+}
+
+// MarshalJSON encodes the tokenizer configuration, or null for a nil tokenizer.
+// It fails if the tokenizer uses a function that cannot be serialized.
+func (t *Tokenizer) MarshalJSON() ([]byte, error) {
+	if t == nil {
+		return []byte("null"), nil
+	}
+	class, ok := tokenClassNames[t.class]
+	if !ok {
+		return nil, fmt.Errorf("invalid token class %d", t.class)
+	}
+	normalize, err := funcName(t.normalizeFunc)
+	if err != nil {
+		return nil, err
+	}
+	// The resolved ranges are saved, so that defaults are kept as they are now
+	minLength, maxLength := t.lengthRange()
+	ngramMin, ngramMax := t.ngramRange()
+	cfg := tokenizerConfig{
+		Class:     class,
+		MinLength: minLength,
+		MaxLength: maxLength,
+		NGramMin:  ngramMin,
+		NGramMax:  ngramMax,
+		NGramSep:  t.ngramSep,
+		Normalize: normalize,
+	}
+	if t.pattern != nil {
+		cfg.Pattern = t.pattern.String()
+	}
+	for _, f := range t.filters {
+		fc, err := marshalFilter(f)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Filters = append(cfg.Filters, fc)
+	}
+	return json.Marshal(cfg)
+}
+
+// UnmarshalJSON replaces the tokenizer configuration with the decoded one.
+func (t *Tokenizer) UnmarshalJSON(data []byte) error {
+	var cfg tokenizerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+	class, ok := lookupName(tokenClassNames, cfg.Class)
+	if !ok {
+		return fmt.Errorf("unknown token class %q", cfg.Class)
+	}
+	normalize, err := namedFunc(cfg.Normalize)
+	if err != nil {
+		return err
+	}
+	var pattern *regexp.Regexp
+	if cfg.Pattern != "" {
+		if pattern, err = regexp.Compile(cfg.Pattern); err != nil {
+			return err
+		}
+	}
+	filters := make([]Filter, 0, len(cfg.Filters))
+	for _, fc := range cfg.Filters {
+		f, err := unmarshalFilter(fc)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, f)
+	}
+
+	*t = Tokenizer{
+		class:         class,
+		pattern:       pattern,
+		normalizeFunc: normalize,
+		minLength:     cfg.MinLength,
+		maxLength:     cfg.MaxLength,
+		lengthSet:     true,
+		ngramMin:      cfg.NGramMin,
+		ngramMax:      cfg.NGramMax,
+		ngramSep:      cfg.NGramSep,
+		filters:       filters,
+		workers:       t.workers,
+	}
+	return nil
+}
+
+// MarshalJSON encodes the tokenizer configuration, or null for a nil tokenizer.
+// It fails if the tokenizer uses a normalization function that cannot be serialized.
+func (t *CharNGramTokenizer) MarshalJSON() ([]byte, error) {
+	if t == nil {
+		return []byte("null"), nil
+	}
+	mode, ok := charModeNames[t.mode]
+	if !ok {
+		return nil, fmt.Errorf("invalid char mode %d", t.mode)
+	}
+	normalize, err := funcName(t.normalizeFunc)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(charNGramConfig{
+		Mode:      mode,
+		NGramMin:  t.ngramMin,
+		NGramMax:  t.ngramMax,
+		Normalize: normalize,
+	})
+}
+
+// UnmarshalJSON replaces the tokenizer configuration with the decoded one.
+func (t *CharNGramTokenizer) UnmarshalJSON(data []byte) error {
+	var cfg charNGramConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+	mode, ok := lookupName(charModeNames, cfg.Mode)
+	if !ok {
+		return fmt.Errorf("unknown char mode %q", cfg.Mode)
+	}
+	normalize, err := namedFunc(cfg.Normalize)
+	if err != nil {
+		return err
+	}
+	*t = CharNGramTokenizer{
+		mode:          mode,
+		ngramMin:      cfg.NGramMin,
+		ngramMax:      cfg.NGramMax,
+		normalizeFunc: normalize,
+		workers:       t.workers,
+	}
+	return nil
+}
+
+// marshalFilter returns the serialized form of a built-in filter.
+func marshalFilter(f Filter) (filterConfig, error) {
+	switch f := f.(type) {
+	case lowercaseFilter:
+		return filterConfig{Type: "lowercase"}, nil
+	case mapFilter:
+		name, err := funcName(f.fn)
+		if err != nil {
+			return filterConfig{}, err
+		}
+		return filterConfig{Type: "map", Func: name}, nil
+	case stopWordsFilter:
+		words := make([]string, 0, len(f.stopWords))
+		for word := range f.stopWords {
+			words = append(words, word)
+		}
+		slices.Sort(words)
+		return filterConfig{Type: "stopwords", Words: words}, nil
+	case stemmerFilter:
+		for lang, stem := range builtinStemmers {
+			if sameFunc(stem, f.stem) {
+				return filterConfig{Type: "stemmer", Language: lang}, nil
+			}
+		}
+		return filterConfig{}, errors.New("stemmer cannot be serialized: only built-in stemmers are supported")
+	case lengthFilter:
+		return filterConfig{Type: "length", Min: f.min, Max: f.max}, nil
+	case synonymFilter:
+		return filterConfig{Type: "synonyms", Synonyms: f.synonyms}, nil
+	default:
+		return filterConfig{}, fmt.Errorf("filter %T cannot be serialized", f)
+	}
+}
+
+// unmarshalFilter rebuilds a built-in filter from its serialized form.
+func unmarshalFilter(fc filterConfig) (Filter, error) {
+	switch fc.Type {
+	case "lowercase":
+		return LowercaseFilter(), nil
+	case "map":
+		fn, err := namedFunc(fc.Func)
+		if err != nil || fn == nil {
+			return nil, fmt.Errorf("unknown map filter function %q", fc.Func)
+		}
+		return MapFilter(fn), nil
+	case "stopwords":
+		return StopWordsFilter(NewStopWords(fc.Words...)), nil
+	case "stemmer":
+		stem, err := NewStemmer(fc.Language)
+		if err != nil {
+			return nil, err
+		}
+		return StemmerFilter(stem), nil
+	case "length":
+		return LengthFilter(fc.Min, fc.Max), nil
+	case "synonyms":
+		return SynonymFilter(fc.Synonyms), nil
+	default:
+		return nil, fmt.Errorf("unknown filter type %q", fc.Type)
+	}
+}
+
+// funcName returns the name of a serializable function, or "" for a nil function.
+func funcName(fn func(string) string) (string, error) {
+	if fn == nil {
+		return "", nil
+	}
+	for name, named := range namedFuncs {
+		if sameFunc(named, fn) {
+			return name, nil
+		}
+	}
+	return "", errors.New("function cannot be serialized: only strings.ToLower and strings.ToUpper are supported")
+}
+
+// namedFunc returns the function with the given name, or nil for "".
+func namedFunc(name string) (func(string) string, error) {
+	if name == "" {
+		return nil, nil
+	}
+	fn, ok := namedFuncs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+	return fn, nil
+}
+
+// sameFunc reports whether a and b are the same package-level function, comparing their code pointers.
+func sameFunc[F ~func(string) string](a, b F) bool {
+	return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+}
+
+// lookupName returns the value registered under name.
+func lookupName[T comparable](names map[T]string, name string) (T, bool) {
+	for value, n := range names {
+		if n == name {
+			return value, true
+		}
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,134 @@
+package token
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTokenizer_JSON(t *testing.T) {
+	english, err := BuiltinStopWords(English)
+	if err != nil {
+		t.Fatalf("BuiltinStopWords error: %v", err)
+	}
+	stem, _ := NewStemmer(English)
+	docs := []string{"The USA animals are running, state-of-the-art 2024", "Ω x"}
+
+	tests := []struct {
+		name string
+		opts []TokenizerOption
+	}{
+		{name: "Default"},
+		{
+			name: "Filters",
+			opts: []TokenizerOption{
+				WithNormalizeFunc(strings.ToLower),
+				WithStopWords(english),
+				WithFilters(
+					SynonymFilter(map[string][]string{"usa": {"united", "states"}}),
+					MapFilter(strings.ToUpper),
+					LowercaseFilter(),
+					LengthFilter(3, 0),
+				),
+				WithStemmer(stem),
+				WithTokenClass(Words),
+				WithTokenLengthRange(1, 20),
+				WithNGramRange(1, 2),
+				WithNGramSeparator("_"),
+			},
+		},
+		{
+			name: "Pattern",
+			opts: []TokenizerOption{WithTokenPattern(regexp.MustCompile(`\b\w\w+\b`))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := NewTokenizer(tt.opts...)
+			data, err := json.Marshal(original)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			var restored Tokenizer
+			if err := json.Unmarshal(data, &restored); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+
+			wantVocab, wantTokens, _ := original.Tokenize(docs)
+			gotVocab, gotTokens, err := restored.Tokenize(docs)
+			if err != nil {
+				t.Fatalf("Tokenize error: %v", err)
+			}
+			if !slices.Equal(gotVocab, wantVocab) {
+				t.Errorf("vocabulary: got %q, want %q", gotVocab, wantVocab)
+			}
+			for i := range wantTokens {
+				if !slices.Equal(gotTokens[i], wantTokens[i]) {
+					t.Errorf("doc %d: got %q, want %q", i, gotTokens[i], wantTokens[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCharNGramTokenizer_JSON(t *testing.T) {
+	original := NewCharNGramTokenizer(WithCharMode(CharWB), WithCharNGramRange(2, 4), WithCharNormalizeFunc(strings.ToLower))
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var restored CharNGramTokenizer
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	docs := []string{"Machine Learning"}
+	want, _, _ := original.Tokenize(docs)
+	got, _, _ := restored.Tokenize(docs)
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestTokenizer_JSON_Nil(t *testing.T) {
+	var tokenizer *Tokenizer
+	var charTokenizer *CharNGramTokenizer
+	for _, marshaler := range []json.Marshaler{tokenizer, charTokenizer} {
+		data, err := marshaler.MarshalJSON()
+		if err != nil || string(data) != "null" {
+			t.Errorf("%T: got %s, %v, want null", marshaler, data, err)
+		}
+	}
+}
+
+func TestTokenizer_JSON_Errors(t *testing.T) {
+	closure := func(s string) string { return s + "_x" }
+	notSerializable := []*Tokenizer{
+		NewTokenizer(WithNormalizeFunc(closure)),
+		NewTokenizer(WithFilters(MapFilter(closure))),
+		NewTokenizer(WithFilters(FilterFunc(func(tokens []string) []string { return tokens }))),
+		NewTokenizer(WithStemmer(func(word string) string { return word })),
+	}
+	for i, tokenizer := range notSerializable {
+		if _, err := json.Marshal(tokenizer); err == nil {
+			t.Errorf("tokenizer %d: Marshal() expected error", i)
+		}
+	}
+
+	invalid := []string{
+		`{"class": "emoji"}`,
+		`{"class": "letters", "normalize": "reverse"}`,
+		`{"class": "letters", "pattern": "("}`,
+		`{"class": "letters", "filters": [{"type": "soundex"}]}`,
+		`{"class": "letters", "filters": [{"type": "stemmer", "language": "klingon"}]}`,
+		`{"class": "letters", "filters": [{"type": "map"}]}`,
+	}
+	for _, data := range invalid {
+		var tokenizer Tokenizer
+		if err := json.Unmarshal([]byte(data), &tokenizer); err == nil {
+			t.Errorf("Unmarshal(%s) expected error", data)
+		}
+	}
+}
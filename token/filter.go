@@ -0,0 +1,172 @@
+package token
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Filter is a stage of the token pipeline. It receives the tokens of a document
+// and returns the tokens to pass on to the next stage, so it can transform, drop
+// or expand tokens. Filters may reuse the backing array of the input slice.
+// Documents are tokenized concurrently (see WithWorkers), so filters must be safe for concurrent use.
+type Filter interface {
+	Filter(tokens []string) []string
+}
+
+// FilterFunc is an adapter to use an ordinary function as a Filter.
+type FilterFunc func(tokens []string) []string
+
+// Filter calls f(tokens).
+func (f FilterFunc) Filter(tokens []string) []string {
+	return f(tokens)
+}
+
+// WithFilters is a functional option to append filters to the token pipeline.
+// Filters run in the order they are given, each exactly once per token, after the
+// normalization function (see WithNormalizeFunc) and before n-grams are built.
+// Options adding filters, like WithStopWords and WithStemmer, append to the same pipeline.
+//
+// Example:
+//
+//	english, _ := BuiltinStopWords(English)
+//	tokenizer := NewTokenizer(WithFilters(
+//		LowercaseFilter(),
+//		StopWordsFilter(english),
+//		StemmerFilter(StemEnglish),
+//		LengthFilter(3, 20),
+//	))
+func WithFilters(filters ...Filter) TokenizerOption {
+	return func(t *Tokenizer) {
+		t.filters = append(t.filters, filters...)
+	}
+}
+
+// mapFilter replaces every token with the result of a function.
+type mapFilter struct {
+	fn func(string) string
+}
+
+// MapFilter returns a filter replacing every token with fn(token).
+func MapFilter(fn func(string) string) Filter {
+	return mapFilter{fn: fn}
+}
+
+func (f mapFilter) Filter(tokens []string) []string {
+	for i, token := range tokens {
+		tokens[i] = f.fn(token)
+	}
+	return tokens
+}
+
+// lowercaseFilter converts every token to lowercase.
+type lowercaseFilter struct{}
+
+// LowercaseFilter returns a filter converting every token to lowercase.
+func LowercaseFilter() Filter {
+	return lowercaseFilter{}
+}
+
+func (lowercaseFilter) Filter(tokens []string) []string {
+	for i, token := range tokens {
+		tokens[i] = strings.ToLower(token)
+	}
+	return tokens
+}
+
+// stopWordsFilter drops the tokens contained in a stop word set.
+type stopWordsFilter struct {
+	stopWords StopWords
+}
+
+// StopWordsFilter returns a filter dropping the stop words.
+func StopWordsFilter(sw StopWords) Filter {
+	return stopWordsFilter{stopWords: sw}
+}
+
+func (f stopWordsFilter) Filter(tokens []string) []string {
+	kept := tokens[:0]
+	for _, token := range tokens {
+		if !f.stopWords.Contains(token) {
+			kept = append(kept, token)
+		}
+	}
+	return kept
+}
+
+// stemmerFilter reduces every token to its stem.
+type stemmerFilter struct {
+	stem Stemmer
+}
+
+// StemmerFilter returns a filter reducing every token to its stem.
+func StemmerFilter(stem Stemmer) Filter {
+	return stemmerFilter{stem: stem}
+}
+
+func (f stemmerFilter) Filter(tokens []string) []string {
+	for i, token := range tokens {
+		tokens[i] = f.stem(token)
+	}
+	return tokens
+}
+
+// lengthFilter drops the tokens whose length in runes is out of bounds.
+type lengthFilter struct {
+	min int
+	max int
+}
+
+// LengthFilter returns a filter keeping only the tokens with at least min and at most max runes.
+// A max of 0 means no upper bound.
+func LengthFilter(min, max int) Filter {
+	return lengthFilter{min: min, max: max}
+}
+
+func (f lengthFilter) Filter(tokens []string) []string {
+	kept := tokens[:0]
+	for _, token := range tokens {
+		if inLength(token, f.min, f.max) {
+			kept = append(kept, token)
+		}
+	}
+	return kept
+}
+
+// inLength reports whether token has at least min and at most max runes, with a max of 0 meaning no upper bound.
+func inLength(token string, min, max int) bool {
+	n := utf8.RuneCountInString(token)
+	return n >= min && (max <= 0 || n <= max)
+}
+
+// synonymFilter replaces tokens with a list of synonyms.
+type synonymFilter struct {
+	synonyms map[string][]string
+}
+
+// SynonymFilter returns a filter replacing each token found in synonyms with the listed tokens.
+// A token can be mapped to a single canonical form, or expanded into several tokens;
+// to keep the original token as well, include it in its own list.
+//
+// Example:
+//
+//	SynonymFilter(map[string][]string{
+//		"usa": {"united", "states"},     // expand
+//		"automobile": {"car"},           // map to a canonical form
+//		"tv": {"tv", "television"},      // keep and expand
+//	})
+func SynonymFilter(synonyms map[string][]string) Filter {
+	return synonymFilter{synonyms: synonyms}
+}
+
+func (f synonymFilter) Filter(tokens []string) []string {
+	// Expansion can grow the slice, so the output cannot reuse the input array.
+	out := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if synonyms, found := f.synonyms[token]; found {
+			out = append(out, synonyms...)
+			continue
+		}
+		out = append(out, token)
+	}
+	return out
+}
@@ -0,0 +1,160 @@
+package token
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   Filter
+		tokens   []string
+		expected []string
+	}{
+		{
+			name:     "Lowercase",
+			filter:   LowercaseFilter(),
+			tokens:   []string{"Big", "BROTHER"},
+			expected: []string{"big", "brother"},
+		},
+		{
+			name:     "Map",
+			filter:   MapFilter(strings.ToUpper),
+			tokens:   []string{"big", "brother"},
+			expected: []string{"BIG", "BROTHER"},
+		},
+		{
+			name:     "Stop words",
+			filter:   StopWordsFilter(NewStopWords("is", "the")),
+			tokens:   []string{"the", "war", "is", "peace"},
+			expected: []string{"war", "peace"},
+		},
+		{
+			name:     "Stemmer",
+			filter:   StemmerFilter(StemEnglish),
+			tokens:   []string{"animals", "equality"},
+			expected: []string{"anim", "equal"},
+		},
+		{
+			name:     "Length in runes",
+			filter:   LengthFilter(3, 4),
+			tokens:   []string{"be", "città", "però", "war", "peace"},
+			expected: []string{"però", "war"},
+		},
+		{
+			name:     "Length without upper bound",
+			filter:   LengthFilter(3, 0),
+			tokens:   []string{"be", "war", "freedom"},
+			expected: []string{"war", "freedom"},
+		},
+		{
+			name: "Synonyms",
+			filter: SynonymFilter(map[string][]string{
+				"usa":        {"united", "states"},
+				"automobile": {"car"},
+				"tv":         {"tv", "television"},
+				"the":        nil,
+			}),
+			tokens:   []string{"the", "usa", "automobile", "tv", "show"},
+			expected: []string{"united", "states", "car", "tv", "television", "show"},
+		},
+		{
+			name: "Func",
+			filter: FilterFunc(func(tokens []string) []string {
+				return tokens[1:]
+			}),
+			tokens:   []string{"first", "second"},
+			expected: []string{"second"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Filter(slices.Clone(tt.tokens))
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTokenizer_Filters(t *testing.T) {
+	suffix := func(s string) string { return s + "_x" }
+	english, err := BuiltinStopWords(English)
+	if err != nil {
+		t.Fatalf("BuiltinStopWords error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     []TokenizerOption
+		doc      string
+		expected []string
+	}{
+		{
+			name:     "Normalization applied once",
+			opts:     []TokenizerOption{WithNormalizeFunc(suffix)},
+			doc:      "war peace",
+			expected: []string{"war_x", "peace_x"},
+		},
+		{
+			name:     "Map filter applied once",
+			opts:     []TokenizerOption{WithFilters(MapFilter(suffix))},
+			doc:      "war peace",
+			expected: []string{"war_x", "peace_x"},
+		},
+		{
+			name: "Filters in order",
+			opts: []TokenizerOption{WithFilters(
+				LowercaseFilter(),
+				StopWordsFilter(english),
+				StemmerFilter(StemEnglish),
+				LengthFilter(4, 0),
+			)},
+			doc:      "The Animals are running",
+			expected: []string{"anim"},
+		},
+		{
+			name: "Order matters",
+			opts: []TokenizerOption{WithFilters(
+				StopWordsFilter(english),
+				LowercaseFilter(),
+			)},
+			doc:      "The animals",
+			expected: []string{"the", "animals"},
+		},
+		{
+			name: "Options append to the pipeline",
+			opts: []TokenizerOption{
+				WithNormalizeFunc(strings.ToLower),
+				WithStopWords(english),
+				WithFilters(SynonymFilter(map[string][]string{"usa": {"united", "states"}})),
+			},
+			doc:      "The USA",
+			expected: []string{"united", "states"},
+		},
+		{
+			name: "Expanded tokens feed n-grams",
+			opts: []TokenizerOption{
+				WithNGramRange(2, 2),
+				WithFilters(SynonymFilter(map[string][]string{"usa": {"united", "states"}})),
+			},
+			doc:      "usa army",
+			expected: []string{"united states", "states army"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, tokens, err := NewTokenizer(tt.opts...).Tokenize([]string{tt.doc})
+			if err != nil {
+				t.Fatalf("Tokenize error: %v", err)
+			}
+			if !slices.Equal(tokens[0], tt.expected) {
+				t.Errorf("got %q, want %q", tokens[0], tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package token
+
+import (
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenClass selects which characters make up a token, for fast scanning without regular expressions.
+type TokenClass int
+
+const (
+	// Letters extracts runs of letters. Digits and symbols separate tokens, so "ISO9001" yields "ISO".
+	Letters TokenClass = iota
+
+	// Alphanumeric extracts runs of letters and digits, keeping codes like "ISO9001" and years like "2024".
+	Alphanumeric
+
+	// Words extracts runs of letters and digits joined by inner hyphens or apostrophes,
+	// so "state-of-the-art" and "don't" are single tokens, while a trailing hyphen is dropped.
+	Words
+
+	// NonSpace extracts runs of any character but white space, keeping symbols like in "C++" or "#golang".
+	NonSpace
+)
+
+// WithTokenClass is a functional option to set the predefined class of characters making up a token.
+// Defaults to Letters.
+func WithTokenClass(class TokenClass) TokenizerOption {
+	return func(t *Tokenizer) {
+		t.class = class
+	}
+}
+
+// WithTokenPattern is a functional option to extract tokens with a regular expression:
+// every non-overlapping match of the pattern is a token. It takes precedence over the token class.
+// Matches are kept whatever their length, unless a range is set with WithTokenLengthRange.
+//
+// Example, a pattern close to scikit-learn's default token_pattern:
+//
+//	tokenizer := NewTokenizer(WithTokenPattern(regexp.MustCompile(`\b\w\w+\b`)))
+func WithTokenPattern(pattern *regexp.Regexp) TokenizerOption {
+	return func(t *Tokenizer) {
+		t.pattern = pattern
+	}
+}
+
+// runes returns the functions reporting whether a rune is part of a token of the class,
+// and whether it joins two token parts. The joiner function is nil when the class has no joiners.
+func (c TokenClass) runes() (inToken, joiner func(rune) bool) {
+	switch c {
+	case Alphanumeric:
+		return isAlphanumeric, nil
+	case Words:
+		return isAlphanumeric, isJoiner
+	case NonSpace:
+		return isNonSpace, nil
+	default:
+		return unicode.IsLetter, nil
+	}
+}
+
+func isAlphanumeric(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isNonSpace(r rune) bool {
+	return !unicode.IsSpace(r)
+}
+
+// isJoiner reports whether r is a hyphen or an apostrophe, including the typographic one.
+func isJoiner(r rune) bool {
+	return r == '-' || r == '\'' || r == '’'
+}
+
+// joined reports whether the joiner r at byte offset i of doc is followed by a token rune,
+// so that it is part of the current token.
+func joined(doc string, i int, r rune, inToken func(rune) bool) bool {
+	next, _ := utf8.DecodeRuneInString(doc[i+utf8.RuneLen(r):])
+	return inToken(next)
+}
@@ -0,0 +1,73 @@
+package token
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestTokenizer_TokenClass(t *testing.T) {
+	doc := "ISO9001 in 2024: state-of-the-art C++ isn't cheap-"
+
+	tests := []struct {
+		name     string
+		opts     []TokenizerOption
+		expected []string
+	}{
+		{
+			name:     "Letters by default",
+			expected: []string{"ISO", "in", "state", "of", "the", "art", "isn", "cheap"},
+		},
+		{
+			name:     "Alphanumeric",
+			opts:     []TokenizerOption{WithTokenClass(Alphanumeric)},
+			expected: []string{"ISO9001", "in", "2024", "state", "of", "the", "art", "isn", "cheap"},
+		},
+		{
+			name:     "Words",
+			opts:     []TokenizerOption{WithTokenClass(Words)},
+			expected: []string{"ISO9001", "in", "2024", "state-of-the-art", "isn't", "cheap"},
+		},
+		{
+			name:     "Non space",
+			opts:     []TokenizerOption{WithTokenClass(NonSpace)},
+			expected: []string{"ISO9001", "in", "2024:", "state-of-the-art", "C++", "isn't", "cheap-"},
+		},
+		{
+			name:     "Pattern",
+			opts:     []TokenizerOption{WithTokenPattern(regexp.MustCompile(`\b\w\w+\b`))},
+			expected: []string{"ISO9001", "in", "2024", "state", "of", "the", "art", "isn", "cheap"},
+		},
+		{
+			name: "Pattern takes precedence over class",
+			opts: []TokenizerOption{
+				WithTokenPattern(regexp.MustCompile(`[0-9]+`)),
+				WithTokenClass(Words),
+			},
+			expected: []string{"9001", "2024"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, tokens, err := NewTokenizer(tt.opts...).Tokenize([]string{doc})
+			if err != nil {
+				t.Fatalf("Tokenize error: %v", err)
+			}
+			if !slices.Equal(tokens[0], tt.expected) {
+				t.Errorf("got %q, want %q", tokens[0], tt.expected)
+			}
+		})
+	}
+}
+
+func TestTokenizer_Words_Typographic(t *testing.T) {
+	_, tokens, err := NewTokenizer(WithTokenClass(Words)).Tokenize([]string{"l’amica dell'arte -- e-mail"})
+	if err != nil {
+		t.Fatalf("Tokenize error: %v", err)
+	}
+	expected := []string{"l’amica", "dell'arte", "e-mail"}
+	if !slices.Equal(tokens[0], expected) {
+		t.Errorf("got %q, want %q", tokens[0], expected)
+	}
+}
@@ -0,0 +1,215 @@
+package token
+
+import (
+	"slices"
+	"unicode/utf8"
+)
+
+// englishVowel reports whether r is a vowel for the English stemmer.
+// 'Y' marks a consonantal y and is not a vowel.
+var englishVowel = runeSet("aeiouy")
+
+// englishExceptions lists words with irregular stems, or that must not be stemmed at all.
+var englishExceptions = map[string]string{
+	"skis":   "ski",
+	"skies":  "sky",
+	"dying":  "die",
+	"lying":  "lie",
+	"tying":  "tie",
+	"idly":   "idl",
+	"gently": "gentl",
+	"ugly":   "ugli",
+	"early":  "earli",
+	"only":   "onli",
+	"singly": "singl",
+	"sky":    "sky",
+	"news":   "news",
+	"howe":   "howe",
+	"atlas":  "atlas",
+	"cosmos": "cosmos",
+	"bias":   "bias",
+	"andes":  "andes",
+}
+
+// englishInvariants lists words left unchanged after step 1a.
+var englishInvariants = []string{
+	"inning", "outing", "canning", "herring", "earring", "proceed", "exceed", "succeed",
+}
+
+// englishStep2 maps the step 2 suffixes to their replacement.
+var englishStep2 = map[string]string{
+	"tional": "tion", "enci": "ence", "anci": "ance", "abli": "able", "entli": "ent",
+	"izer": "ize", "ization": "ize", "ational": "ate", "ation": "ate", "ator": "ate",
+	"alism": "al", "aliti": "al", "alli": "al", "fulness": "ful", "ousli": "ous",
+	"ousness": "ous", "iveness": "ive", "iviti": "ive", "biliti": "ble", "bli": "ble",
+	"ogi": "og", "fulli": "ful", "lessli": "less", "li": "",
+}
+
+// englishStep3 maps the step 3 suffixes to their replacement.
+var englishStep3 = map[string]string{
+	"tional": "tion", "ational": "ate", "alize": "al", "icate": "ic", "iciti": "ic",
+	"ical": "ic", "ful": "", "ness": "", "ative": "",
+}
+
+var (
+	englishStep2Suffixes = suffixKeys(englishStep2)
+	englishStep3Suffixes = suffixKeys(englishStep3)
+)
+
+// englishStep4 lists the step 4 suffixes, deleted when in R2.
+var englishStep4 = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+	"ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+}
+
+// StemEnglish stems an English word with the Porter2 (Snowball English) algorithm,
+// see https://snowballstem.org/algorithms/english/stemmer.html.
+// For example "animals" and "animal" both become "anim", and "equality" becomes "equal".
+func StemEnglish(word string) string {
+	if utf8.RuneCountInString(word) <= 2 {
+		return word
+	}
+	if stem, found := englishExceptions[word]; found {
+		return stem
+	}
+
+	w := []rune(word)
+	// Prelude: drop a leading apostrophe, and mark consonantal y as Y.
+	if w[0] == '\'' {
+		w = w[1:]
+	}
+	for i, r := range w {
+		if r == 'y' && (i == 0 || englishVowel(w[i-1])) {
+			w[i] = 'Y'
+		}
+	}
+
+	r1, r2 := regions(w, englishVowel)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if len(w) >= len(prefix) && string(w[:len(prefix)]) == prefix {
+			r1 = len(prefix)
+			r2 = nextRegion(w, r1, englishVowel)
+			break
+		}
+	}
+
+	// Step 0: possessives.
+	if suffix, pos := longestSuffix(w, []string{"'s'", "'s", "'"}); suffix != "" {
+		w = w[:pos]
+	}
+
+	// Step 1a: plurals.
+	switch suffix, pos := longestSuffix(w, []string{"sses", "ied", "ies", "us", "ss", "s"}); suffix {
+	case "sses":
+		w = replaceSuffix(w, 4, "ss")
+	case "ied", "ies":
+		if pos > 1 {
+			w = replaceSuffix(w, 3, "i")
+		} else {
+			w = replaceSuffix(w, 3, "ie")
+		}
+	case "s":
+		if pos > 1 && slices.ContainsFunc(w[:pos-1], englishVowel) {
+			w = w[:pos]
+		}
+	}
+	if slices.Contains(englishInvariants, string(w)) {
+		return string(w)
+	}
+
+	// Step 1b: past tenses and gerunds.
+	switch suffix, pos := longestSuffix(w, []string{"eed", "eedly", "ed", "edly", "ing", "ingly"}); suffix {
+	case "eed", "eedly":
+		if pos >= r1 {
+			w = append(w[:pos], 'e', 'e')
+		}
+	case "ed", "edly", "ing", "ingly":
+		if slices.ContainsFunc(w[:pos], englishVowel) {
+			w = w[:pos]
+			switch {
+			case hasSuffix(w, "at"), hasSuffix(w, "bl"), hasSuffix(w, "iz"):
+				w = append(w, 'e')
+			case englishEndsWithDouble(w):
+				w = w[:len(w)-1]
+			case r1 >= len(w) && englishEndsWithShortSyllable(w):
+				w = append(w, 'e')
+			}
+		}
+	}
+
+	// Step 1c: final y preceded by a consonant that is not the first letter.
+	if n := len(w); n > 2 && (w[n-1] == 'y' || w[n-1] == 'Y') && !englishVowel(w[n-2]) {
+		w[n-1] = 'i'
+	}
+
+	// Step 2: derivational suffixes in R1.
+	if suffix, pos := longestSuffix(w, englishStep2Suffixes); suffix != "" && pos >= r1 {
+		switch suffix {
+		case "ogi":
+			if pos > 0 && w[pos-1] == 'l' {
+				w = replaceSuffix(w, 3, "og")
+			}
+		case "li":
+			if pos > 0 && slices.Contains([]rune("cdeghkmnrt"), w[pos-1]) {
+				w = w[:pos]
+			}
+		default:
+			w = replaceSuffix(w, utf8.RuneCountInString(suffix), englishStep2[suffix])
+		}
+	}
+
+	// Step 3: more derivational suffixes in R1.
+	if suffix, pos := longestSuffix(w, englishStep3Suffixes); suffix != "" && pos >= r1 {
+		if suffix != "ative" || pos >= r2 {
+			w = replaceSuffix(w, utf8.RuneCountInString(suffix), englishStep3[suffix])
+		}
+	}
+
+	// Step 4: residual suffixes in R2.
+	if suffix, pos := longestSuffix(w, englishStep4); suffix != "" && pos >= r2 {
+		if suffix != "ion" || (pos > 0 && (w[pos-1] == 's' || w[pos-1] == 't')) {
+			w = w[:pos]
+		}
+	}
+
+	// Step 5: final e and double l.
+	if n := len(w); n > 0 {
+		switch pos := n - 1; w[pos] {
+		case 'e':
+			if pos >= r2 || (pos >= r1 && !englishEndsWithShortSyllable(w[:pos])) {
+				w = w[:pos]
+			}
+		case 'l':
+			if pos >= r2 && pos > 0 && w[pos-1] == 'l' {
+				w = w[:pos]
+			}
+		}
+	}
+
+	// Postlude: restore the consonantal y.
+	for i, r := range w {
+		if r == 'Y' {
+			w[i] = 'y'
+		}
+	}
+	return string(w)
+}
+
+// englishEndsWithDouble reports whether the word ends with one of bb dd ff gg mm nn pp rr tt.
+func englishEndsWithDouble(w []rune) bool {
+	n := len(w)
+	return n >= 2 && w[n-1] == w[n-2] && slices.Contains([]rune("bdfgmnprt"), w[n-1])
+}
+
+// englishEndsWithShortSyllable reports whether the word ends with a short syllable:
+// a vowel followed by a non-vowel other than w, x or Y and preceded by a non-vowel,
+// or a vowel followed by a non-vowel at the beginning of the word.
+func englishEndsWithShortSyllable(w []rune) bool {
+	n := len(w)
+	if n == 2 {
+		return englishVowel(w[0]) && !englishVowel(w[1])
+	}
+	return n >= 3 &&
+		!englishVowel(w[n-3]) && englishVowel(w[n-2]) && !englishVowel(w[n-1]) &&
+		w[n-1] != 'w' && w[n-1] != 'x' && w[n-1] != 'Y'
+}
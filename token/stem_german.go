@@ -0,0 +1,107 @@
+package token
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// germanVowel reports whether r is a vowel for the German stemmer.
+// 'U' and 'Y' mark a u or y between vowels, which is treated as a consonant.
+var germanVowel = runeSet("aeiouyäöü")
+
+// StemGerman stems a German word with the Snowball German algorithm,
+// see https://snowballstem.org/algorithms/german/stemmer.html.
+// For example "häuser" becomes "haus" and "katzen" becomes "katz".
+func StemGerman(word string) string {
+	// Prelude: replace ß by ss, and mark u and y between vowels as consonants.
+	w := []rune(strings.ReplaceAll(word, "ß", "ss"))
+	for i := 1; i < len(w)-1; i++ {
+		if (w[i] == 'u' || w[i] == 'y') && germanVowel(w[i-1]) && germanVowel(w[i+1]) {
+			w[i] = unicode.ToUpper(w[i])
+		}
+	}
+
+	// R1 is adjusted so that the region before it contains at least 3 letters.
+	r1, r2 := len(w), len(w)
+	if len(w) >= 3 {
+		r1, r2 = regions(w, germanVowel)
+		r1 = max(r1, 3)
+	}
+
+	// Step 1: inflectional suffixes in R1.
+	switch suffix, pos := longestSuffix(w, []string{"em", "ern", "er", "e", "en", "es", "s"}); suffix {
+	case "em", "ern", "er":
+		if pos >= r1 {
+			w = w[:pos]
+		}
+	case "e", "en", "es":
+		if pos >= r1 {
+			w = w[:pos]
+			if hasSuffix(w, "niss") {
+				w = w[:len(w)-1]
+			}
+		}
+	case "s":
+		if pos >= r1 && pos > 0 && slices.Contains([]rune("bdfghklmnrt"), w[pos-1]) {
+			w = w[:pos]
+		}
+	}
+
+	// Step 2: more inflectional suffixes in R1.
+	switch suffix, pos := longestSuffix(w, []string{"en", "er", "est", "st"}); suffix {
+	case "en", "er", "est":
+		if pos >= r1 {
+			w = w[:pos]
+		}
+	case "st":
+		// st must follow a valid st-ending, itself preceded by at least 3 letters.
+		if pos >= r1 && pos >= 4 && slices.Contains([]rune("bdfghklmnt"), w[pos-1]) {
+			w = w[:pos]
+		}
+	}
+
+	// Step 3: derivational suffixes in R2.
+	switch suffix, pos := longestSuffix(w, []string{"end", "ung", "ig", "ik", "isch", "lich", "heit", "keit"}); suffix {
+	case "end", "ung":
+		if pos >= r2 {
+			w = w[:pos]
+			if p := len(w) - 2; hasSuffix(w, "ig") && p >= r2 && (p == 0 || w[p-1] != 'e') {
+				w = w[:p]
+			}
+		}
+	case "ig", "ik", "isch":
+		if pos >= r2 && (pos == 0 || w[pos-1] != 'e') {
+			w = w[:pos]
+		}
+	case "lich", "heit":
+		if pos >= r2 {
+			w = w[:pos]
+			if p := len(w) - 2; (hasSuffix(w, "er") || hasSuffix(w, "en")) && p >= r1 {
+				w = w[:p]
+			}
+		}
+	case "keit":
+		if pos >= r2 {
+			w = w[:pos]
+			if suffix, p := longestSuffix(w, []string{"lich", "ig"}); suffix != "" && p >= r2 {
+				w = w[:p]
+			}
+		}
+	}
+
+	// Postlude: restore u and y, and remove umlauts.
+	for i, r := range w {
+		switch r {
+		case 'U', 'ü':
+			w[i] = 'u'
+		case 'Y':
+			w[i] = 'y'
+		case 'ä':
+			w[i] = 'a'
+		case 'ö':
+			w[i] = 'o'
+		}
+	}
+	return string(w)
+}
@@ -0,0 +1,195 @@
+package token
+
+import "unicode"
+
+// italianVowel reports whether r is a vowel for the Italian stemmer.
+// 'I' and 'U' mark an i or u between vowels, which is treated as a consonant.
+var italianVowel = runeSet("aeiouàèìòù")
+
+// italianPronouns lists the attached pronoun suffixes removed in step 0.
+var italianPronouns = []string{
+	"ci", "gli", "la", "le", "li", "lo", "mi", "ne", "si", "ti", "vi",
+	"sene", "gliela", "gliele", "glieli", "glielo", "gliene",
+	"mela", "mele", "meli", "melo", "mene", "tela", "tele", "teli", "telo", "tene",
+	"cela", "cele", "celi", "celo", "cene", "vela", "vele", "veli", "velo", "vene",
+}
+
+// italianStep1 lists the standard suffixes of step 1.
+var italianStep1 = []string{
+	"anza", "anze", "ico", "ici", "ica", "ice", "iche", "ichi", "ismo", "ismi",
+	"abile", "abili", "ibile", "ibili", "ista", "iste", "isti", "istà", "istè", "istì",
+	"oso", "osi", "osa", "ose", "mente", "atrice", "atrici", "ante", "anti",
+	"azione", "azioni", "atore", "atori", "logia", "logie",
+	"uzione", "uzioni", "usione", "usioni", "enza", "enze",
+	"amento", "amenti", "imento", "imenti", "amente", "ità", "ivo", "ivi", "iva", "ive",
+}
+
+// italianStep2 lists the verb suffixes of step 2.
+var italianStep2 = []string{
+	"ammo", "ando", "ano", "are", "arono", "asse", "assero", "assi", "assimo", "ata", "ate",
+	"ati", "ato", "ava", "avamo", "avano", "avate", "avi", "avo", "emmo", "enda", "ende",
+	"endi", "endo", "erà", "erai", "eranno", "ere", "erebbe", "erebbero", "erei", "eremmo",
+	"eremo", "ereste", "eresti", "erete", "erò", "erono", "essero", "ete", "eva", "evamo",
+	"evano", "evate", "evi", "evo", "iamo", "immo", "irà", "irai", "iranno", "ire",
+	"irebbe", "irebbero", "irei", "iremmo", "iremo", "ireste", "iresti", "irete", "irò",
+	"irono", "isca", "iscano", "isce", "isci", "isco", "iscono", "issero", "ita", "ite",
+	"iti", "ito", "iva", "ivamo", "ivano", "ivate", "ivi", "ivo", "ono", "uta", "ute",
+	"uti", "uto", "ar", "ir",
+}
+
+// StemItalian stems an Italian word with the Snowball Italian algorithm,
+// see https://snowballstem.org/algorithms/italian/stemmer.html.
+// For example "abbandonata" and "abbandonati" both become "abbandon".
+func StemItalian(word string) string {
+	// Prelude: normalize acute accents to grave, and mark u after q, and i or u
+	// between vowels, as consonants.
+	w := []rune(word)
+	for i, r := range w {
+		switch r {
+		case 'á':
+			w[i] = 'à'
+		case 'é':
+			w[i] = 'è'
+		case 'í':
+			w[i] = 'ì'
+		case 'ó':
+			w[i] = 'ò'
+		case 'ú':
+			w[i] = 'ù'
+		case 'u':
+			if i > 0 && w[i-1] == 'q' {
+				w[i] = 'U'
+			}
+		}
+	}
+	for i := 1; i < len(w)-1; i++ {
+		if (w[i] == 'i' || w[i] == 'u') && italianVowel(w[i-1]) && italianVowel(w[i+1]) {
+			w[i] = unicode.ToUpper(w[i])
+		}
+	}
+
+	rv := regionRV(w, italianVowel)
+	r1, r2 := regions(w, italianVowel)
+
+	// Step 0: attached pronouns following a gerund or an infinitive.
+	if suffix, pos := longestSuffix(w, italianPronouns); suffix != "" && pos >= rv {
+		switch ending, p := longestSuffix(w[:pos], []string{"ando", "endo", "ar", "er", "ir"}); ending {
+		case "ando", "endo":
+			if p >= rv {
+				w = w[:pos]
+			}
+		case "ar", "er", "ir":
+			if p >= rv {
+				w = append(w[:pos], 'e')
+			}
+		}
+	}
+
+	// Step 1: standard suffixes, then verb suffixes if none was removed.
+	var removed bool
+	w, removed = italianStandardSuffix(w, rv, r1, r2)
+	if !removed {
+		if suffix, pos := longestSuffix(w, italianStep2); suffix != "" && pos >= rv {
+			w = w[:pos]
+		}
+	}
+
+	// Step 3a: final vowel in RV, and a preceding i in RV.
+	if n := len(w); n > 0 && n-1 >= rv && italianVowel(w[n-1]) && w[n-1] != 'u' && w[n-1] != 'ù' {
+		w = w[:n-1]
+		if n := len(w); n > 0 && n-1 >= rv && w[n-1] == 'i' {
+			w = w[:n-1]
+		}
+	}
+
+	// Step 3b: ch and gh in RV become c and g.
+	if n := len(w); (hasSuffix(w, "ch") || hasSuffix(w, "gh")) && n-2 >= rv {
+		w = w[:n-1]
+	}
+
+	// Postlude: restore the marked i and u.
+	for i, r := range w {
+		switch r {
+		case 'I':
+			w[i] = 'i'
+		case 'U':
+			w[i] = 'u'
+		}
+	}
+	return string(w)
+}
+
+// italianStandardSuffix implements step 1 of the Italian stemmer, and reports whether a suffix was removed.
+func italianStandardSuffix(w []rune, rv, r1, r2 int) ([]rune, bool) {
+	suffix, pos := longestSuffix(w, italianStep1)
+	switch suffix {
+	case "":
+		return w, false
+	case "azione", "azioni", "atore", "atori":
+		if pos < r2 {
+			return w, false
+		}
+		w = w[:pos]
+		if p := len(w) - 2; hasSuffix(w, "ic") && p >= r2 {
+			w = w[:p]
+		}
+	case "logia", "logie":
+		if pos < r2 {
+			return w, false
+		}
+		w = replaceSuffix(w, len(w)-pos, "log")
+	case "uzione", "uzioni", "usione", "usioni":
+		if pos < r2 {
+			return w, false
+		}
+		w = replaceSuffix(w, len(w)-pos, "u")
+	case "enza", "enze":
+		if pos < r2 {
+			return w, false
+		}
+		w = replaceSuffix(w, len(w)-pos, "ente")
+	case "amento", "amenti", "imento", "imenti":
+		if pos < rv {
+			return w, false
+		}
+		w = w[:pos]
+	case "amente":
+		if pos < r1 {
+			return w, false
+		}
+		w = w[:pos]
+		if p := len(w) - 2; hasSuffix(w, "iv") && p >= r2 {
+			w = w[:p]
+			if p := len(w) - 2; hasSuffix(w, "at") && p >= r2 {
+				w = w[:p]
+			}
+		} else if s, p := longestSuffix(w, []string{"os", "ic", "abil"}); s != "" && p >= r2 {
+			w = w[:p]
+		}
+	case "ità":
+		if pos < r2 {
+			return w, false
+		}
+		w = w[:pos]
+		if s, p := longestSuffix(w, []string{"abil", "ic", "iv"}); s != "" && p >= r2 {
+			w = w[:p]
+		}
+	case "ivo", "ivi", "iva", "ive":
+		if pos < r2 {
+			return w, false
+		}
+		w = w[:pos]
+		if p := len(w) - 2; hasSuffix(w, "at") && p >= r2 {
+			w = w[:p]
+			if p := len(w) - 2; hasSuffix(w, "ic") && p >= r2 {
+				w = w[:p]
+			}
+		}
+	default:
+		if pos < r2 {
+			return w, false
+		}
+		w = w[:pos]
+	}
+	return w, true
+}
@@ -0,0 +1,185 @@
+package token
+
+// spanishVowel reports whether r is a vowel for the Spanish stemmer.
+var spanishVowel = runeSet("aeiouáéíóúü")
+
+// spanishPronouns lists the attached pronoun suffixes removed in step 0.
+var spanishPronouns = []string{
+	"me", "se", "sela", "selo", "selas", "selos", "la", "le", "lo", "las", "les", "los", "nos",
+}
+
+// spanishStep1 lists the standard suffixes of step 1.
+var spanishStep1 = []string{
+	"anza", "anzas", "ico", "ica", "icos", "icas", "ismo", "ismos", "able", "ables", "ible",
+	"ibles", "ista", "istas", "oso", "osa", "osos", "osas", "amiento", "amientos", "imiento",
+	"imientos", "adora", "ador", "ación", "adoras", "adores", "aciones", "ante", "antes",
+	"ancia", "ancias", "logía", "logías", "ución", "uciones", "encia", "encias", "amente",
+	"mente", "idad", "idades", "iva", "ivo", "ivas", "ivos",
+}
+
+// spanishStep2a lists the verb suffixes beginning with y of step 2a.
+var spanishStep2a = []string{
+	"ya", "ye", "yan", "yen", "yeron", "yendo", "yo", "yó", "yas", "yes", "yais", "yamos",
+}
+
+// spanishStep2b lists the other verb suffixes of step 2b.
+var spanishStep2b = []string{
+	"en", "es", "éis", "emos",
+	"arían", "arías", "arán", "arás", "aríais", "aría", "aréis", "aríamos", "aremos", "ará",
+	"aré", "erían", "erías", "erán", "erás", "eríais", "ería", "eréis", "eríamos", "eremos",
+	"erá", "eré", "irían", "irías", "irán", "irás", "iríais", "iría", "iréis", "iríamos",
+	"iremos", "irá", "iré", "aba", "ada", "ida", "ía", "ara", "iera", "ad", "ed", "id",
+	"ase", "iese", "aste", "iste", "an", "aban", "ían", "aran", "ieran", "asen", "iesen",
+	"aron", "ieron", "ado", "ido", "ando", "iendo", "ió", "ar", "er", "ir", "as", "abas",
+	"adas", "idas", "ías", "aras", "ieras", "ases", "ieses", "ís", "áis", "abais", "íais",
+	"arais", "ierais", "aseis", "ieseis", "asteis", "isteis", "ados", "idos", "amos",
+	"ábamos", "íamos", "imos", "áramos", "iéramos", "iésemos", "ásemos",
+}
+
+// StemSpanish stems a Spanish word with the Snowball Spanish algorithm,
+// see https://snowballstem.org/algorithms/spanish/stemmer.html.
+// For example "chicas" becomes "chic" and "acelerador" becomes "aceler".
+func StemSpanish(word string) string {
+	w := []rune(word)
+	rv := regionRV(w, spanishVowel)
+	r1, r2 := regions(w, spanishVowel)
+
+	// Step 0: attached pronouns following a gerund or an infinitive.
+	if suffix, pos := longestSuffix(w, spanishPronouns); suffix != "" && pos >= rv {
+		ending, p := longestSuffix(w[:pos], []string{"iéndo", "ándo", "ár", "ér", "ír", "ando", "iendo", "ar", "er", "ir", "yendo"})
+		switch {
+		case ending == "" || p < rv:
+		case ending == "yendo" && (p == 0 || w[p-1] != 'u'):
+		default:
+			w = removeAccents(w[:pos], p)
+		}
+	}
+
+	// Step 1: standard suffixes, then verb suffixes if none was removed.
+	var removed bool
+	w, removed = spanishStandardSuffix(w, rv, r1, r2)
+	if !removed {
+		// Step 2a: verb suffixes beginning with y, after u.
+		if suffix, pos := longestSuffix(w, spanishStep2a); suffix != "" && pos >= rv && pos > 0 && w[pos-1] == 'u' {
+			w = w[:pos]
+		} else if suffix, pos := longestSuffix(w, spanishStep2b); suffix != "" && pos >= rv {
+			// Step 2b: other verb suffixes.
+			w = w[:pos]
+			if (suffix == "en" || suffix == "es" || suffix == "éis" || suffix == "emos") && hasSuffix(w, "gu") {
+				w = w[:len(w)-1]
+			}
+		}
+	}
+
+	// Step 3: residual suffixes in RV.
+	switch suffix, pos := longestSuffix(w, []string{"os", "a", "o", "á", "í", "ó", "e", "é"}); suffix {
+	case "os", "a", "o", "á", "í", "ó":
+		if pos >= rv {
+			w = w[:pos]
+		}
+	case "e", "é":
+		if pos >= rv {
+			w = w[:pos]
+			if p := len(w) - 1; hasSuffix(w, "gu") && p >= rv {
+				w = w[:p]
+			}
+		}
+	}
+
+	// Postlude: remove acute accents.
+	return string(removeAccents(w, 0))
+}
+
+// spanishStandardSuffix implements step 1 of the Spanish stemmer, and reports whether a suffix was removed.
+func spanishStandardSuffix(w []rune, rv, r1, r2 int) ([]rune, bool) {
+	suffix, pos := longestSuffix(w, spanishStep1)
+	switch suffix {
+	case "":
+		return w, false
+	case "adora", "ador", "ación", "adoras", "adores", "aciones", "ante", "antes", "ancia", "ancias":
+		if pos < r2 {
+			return w, false
+		}
+		w = w[:pos]
+		if p := len(w) - 2; hasSuffix(w, "ic") && p >= r2 {
+			w = w[:p]
+		}
+	case "logía", "logías":
+		if pos < r2 {
+			return w, false
+		}
+		w = replaceSuffix(w, len(w)-pos, "log")
+	case "ución", "uciones":
+		if pos < r2 {
+			return w, false
+		}
+		w = replaceSuffix(w, len(w)-pos, "u")
+	case "encia", "encias":
+		if pos < r2 {
+			return w, false
+		}
+		w = replaceSuffix(w, len(w)-pos, "ente")
+	case "amente":
+		if pos < r1 {
+			return w, false
+		}
+		w = w[:pos]
+		if p := len(w) - 2; hasSuffix(w, "iv") && p >= r2 {
+			w = w[:p]
+			if p := len(w) - 2; hasSuffix(w, "at") && p >= r2 {
+				w = w[:p]
+			}
+		} else if s, p := longestSuffix(w, []string{"os", "ic", "ad"}); s != "" && p >= r2 {
+			w = w[:p]
+		}
+	case "mente":
+		if pos < r2 {
+			return w, false
+		}
+		w = w[:pos]
+		if s, p := longestSuffix(w, []string{"ante", "able", "ible"}); s != "" && p >= r2 {
+			w = w[:p]
+		}
+	case "idad", "idades":
+		if pos < r2 {
+			return w, false
+		}
+		w = w[:pos]
+		if s, p := longestSuffix(w, []string{"abil", "ic", "iv"}); s != "" && p >= r2 {
+			w = w[:p]
+		}
+	case "iva", "ivo", "ivas", "ivos":
+		if pos < r2 {
+			return w, false
+		}
+		w = w[:pos]
+		if p := len(w) - 2; hasSuffix(w, "at") && p >= r2 {
+			w = w[:p]
+		}
+	default:
+		if pos < r2 {
+			return w, false
+		}
+		w = w[:pos]
+	}
+	return w, true
+}
+
+// removeAccents replaces the acute accented vowels of the word from position start with plain vowels.
+func removeAccents(w []rune, start int) []rune {
+	for i := start; i < len(w); i++ {
+		switch w[i] {
+		case 'á':
+			w[i] = 'a'
+		case 'é':
+			w[i] = 'e'
+		case 'í':
+			w[i] = 'i'
+		case 'ó':
+			w[i] = 'o'
+		case 'ú':
+			w[i] = 'u'
+		}
+	}
+	return w
+}
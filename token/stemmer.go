@@ -0,0 +1,146 @@
+package token
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Stemmer reduces an inflected word to its stem, e.g. "animals" to "anim".
+// Stemmers expect lowercase words.
+type Stemmer func(word string) string
+
+// NewStemmer returns the Snowball stemmer for a language.
+// English, German, Italian and Spanish are supported.
+func NewStemmer(lang Language) (Stemmer, error) {
+	switch lang {
+	case English:
+		return StemEnglish, nil
+	case German:
+		return StemGerman, nil
+	case Italian:
+		return StemItalian, nil
+	case Spanish:
+		return StemSpanish, nil
+	default:
+		return nil, fmt.Errorf("no stemmer for language %q", lang)
+	}
+}
+
+// WithStemmer is a functional option to reduce every token to its stem.
+// It appends a StemmerFilter to the filter pipeline, so stemming is applied once per token,
+// after normalization, in the order the options are given, and before n-grams are built.
+// Stop words should be removed before stemming, since the built-in lists are not stemmed.
+//
+// Example:
+//
+//	stem, _ := NewStemmer(English)
+//	tokenizer := NewTokenizer(WithNormalizeFunc(strings.ToLower), WithStopWords(english), WithStemmer(stem))
+func WithStemmer(stem Stemmer) TokenizerOption {
+	return WithFilters(StemmerFilter(stem))
+}
+
+// The helpers below implement the regions and suffix lookups shared by the Snowball
+// stemmers (see https://snowballstem.org/texts/r1r2.html). Words are handled as runes,
+// so that regions are measured in letters rather than bytes.
+
+// regions returns the start of the R1 and R2 regions of a word.
+// R1 is the region after the first non-vowel following a vowel, or the end of the word
+// if there is no such non-vowel. R2 is the region obtained applying the same rule to R1.
+func regions(w []rune, isVowel func(rune) bool) (r1, r2 int) {
+	r1 = nextRegion(w, 0, isVowel)
+	r2 = nextRegion(w, r1, isVowel)
+	return r1, r2
+}
+
+// nextRegion returns the position following the first non-vowel after a vowel, starting from start.
+func nextRegion(w []rune, start int, isVowel func(rune) bool) int {
+	for i := start + 1; i < len(w); i++ {
+		if !isVowel(w[i]) && isVowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+// regionRV returns the start of the RV region used by the Romance language stemmers.
+// If the second letter is a consonant, RV is the region after the next following vowel;
+// if the first two letters are vowels, RV is the region after the next consonant;
+// otherwise RV is the region after the third letter. RV is the end of the word if
+// these positions cannot be found.
+func regionRV(w []rune, isVowel func(rune) bool) int {
+	if len(w) < 2 {
+		return len(w)
+	}
+	if !isVowel(w[1]) {
+		for i := 2; i < len(w); i++ {
+			if isVowel(w[i]) {
+				return i + 1
+			}
+		}
+		return len(w)
+	}
+	if isVowel(w[0]) {
+		for i := 2; i < len(w); i++ {
+			if !isVowel(w[i]) {
+				return i + 1
+			}
+		}
+		return len(w)
+	}
+	return min(3, len(w))
+}
+
+// hasSuffix reports whether the word ends with suffix.
+func hasSuffix(w []rune, suffix string) bool {
+	n := utf8.RuneCountInString(suffix)
+	if n > len(w) {
+		return false
+	}
+	i := len(w) - n
+	for _, r := range suffix {
+		if w[i] != r {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// longestSuffix returns the longest of the suffixes the word ends with, and its position
+// in the word. It returns an empty suffix and len(w) if none matches.
+func longestSuffix(w []rune, suffixes []string) (string, int) {
+	best, bestLen := "", 0
+	for _, suffix := range suffixes {
+		n := utf8.RuneCountInString(suffix)
+		if n > bestLen && hasSuffix(w, suffix) {
+			best, bestLen = suffix, n
+		}
+	}
+	return best, len(w) - bestLen
+}
+
+// suffixKeys returns the suffixes of a suffix -> replacement map.
+func suffixKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for suffix := range m {
+		keys = append(keys, suffix)
+	}
+	return keys
+}
+
+// replaceSuffix replaces the last n runes of the word with repl.
+func replaceSuffix(w []rune, n int, repl string) []rune {
+	return append(w[:len(w)-n], []rune(repl)...)
+}
+
+// runeSet builds a membership predicate over a set of runes.
+func runeSet(runes string) func(rune) bool {
+	set := make(map[rune]struct{})
+	for _, r := range runes {
+		set[r] = struct{}{}
+	}
+	return func(r rune) bool {
+		_, found := set[r]
+		return found
+	}
+}
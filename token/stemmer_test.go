@@ -0,0 +1,97 @@
+package token
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStemEnglish(t *testing.T) {
+	tests := map[string]string{
+		"animals": "anim", "animal": "anim", "equality": "equal", "equal": "equal",
+		"consign": "consign", "consigned": "consign", "consigning": "consign", "consignment": "consign",
+		"consistency": "consist", "consistently": "consist", "consists": "consist",
+		"consolation": "consol", "consolatory": "consolatori", "consoled": "consol", "consolingly": "consol",
+		"conspicuously": "conspicu", "conspiracy": "conspiraci", "conspirators": "conspir",
+		"constable": "constabl", "constancy": "constanc", "constant": "constant",
+		"knackeries": "knackeri", "knave": "knave", "knives": "knive", "kneaded": "knead",
+		"knightly": "knight", "knitting": "knit", "knocker": "knocker",
+		"generously": "generous", "hopping": "hop", "hoped": "hope", "luxuriating": "luxuri",
+		"cries": "cri", "ties": "tie", "gaps": "gap", "gas": "gas", "kiwis": "kiwi",
+		"caresses": "caress", "sky": "sky", "skies": "sky", "dying": "die", "succeeding": "succeed",
+		"succeed": "succeed", "happily": "happili", "relational": "relat", "saying": "say",
+		"boy's": "boy", "at": "at",
+	}
+	for word, want := range tests {
+		if got := StemEnglish(word); got != want {
+			t.Errorf("StemEnglish(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestStemGerman(t *testing.T) {
+	tests := map[string]string{
+		"häuser": "haus", "katzen": "katz", "laufen": "lauf", "abenteuer": "abenteu",
+		"abends": "abend", "aufeinanderfolgenden": "aufeinanderfolg", "straße": "strass",
+		"freundlichkeit": "freundlich", "kenntnisse": "kenntnis", "bedeutung": "bedeut",
+	}
+	for word, want := range tests {
+		if got := StemGerman(word); got != want {
+			t.Errorf("StemGerman(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestStemItalian(t *testing.T) {
+	tests := map[string]string{
+		"abbandonata": "abbandon", "abbandonate": "abbandon", "abbandonati": "abbandon",
+		"abbandonato": "abbandon", "abitazione": "abit", "abbattimento": "abbatt",
+		"mangiarlo": "mang", "velocemente": "veloc", "gatti": "gatt", "gatto": "gatt",
+	}
+	for word, want := range tests {
+		if got := StemItalian(word); got != want {
+			t.Errorf("StemItalian(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestStemSpanish(t *testing.T) {
+	tests := map[string]string{
+		"chicas": "chic", "chico": "chic", "cantaba": "cant", "acelerador": "aceler",
+		"abarcaba": "abarc", "torniquete": "torniquet", "rápidamente": "rapid",
+		"comiéndolo": "com", "nación": "nacion",
+	}
+	for word, want := range tests {
+		if got := StemSpanish(word); got != want {
+			t.Errorf("StemSpanish(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestTokenizer_Stemmer(t *testing.T) {
+	stem, err := NewStemmer(English)
+	if err != nil {
+		t.Fatalf("NewStemmer error: %v", err)
+	}
+	english, err := BuiltinStopWords(English)
+	if err != nil {
+		t.Fatalf("BuiltinStopWords error: %v", err)
+	}
+
+	tokenizer := NewTokenizer(WithNormalizeFunc(strings.ToLower), WithStopWords(english), WithStemmer(stem))
+	vocab, _, err := tokenizer.Tokenize([]string{
+		"All animals are equal",
+		"equality among animals",
+	})
+	if err != nil {
+		t.Fatalf("Tokenize error: %v", err)
+	}
+	want := []string{"among", "anim", "equal"}
+	if !slices.Equal(vocab, want) {
+		t.Errorf("got %q, want %q", vocab, want)
+	}
+
+	if _, err := NewStemmer("klingon"); err == nil {
+		t.Errorf("NewStemmer() expected error on unknown language")
+	}
+}
@@ -0,0 +1,94 @@
+package token
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Language identifies a natural language supported by the built-in resources of this package.
+type Language string
+
+const (
+	English Language = "english"
+	Italian Language = "italian"
+	German  Language = "german"
+	French  Language = "french"
+	Spanish Language = "spanish"
+)
+
+//go:embed stopwords/*.txt
+var stopWordsFS embed.FS
+
+// StopWords is a set of words to be dropped from the token stream.
+type StopWords map[string]struct{}
+
+// NewStopWords creates a stop word set from the given words.
+func NewStopWords(words ...string) StopWords {
+	sw := make(StopWords, len(words))
+	for _, word := range words {
+		sw[word] = struct{}{}
+	}
+	return sw
+}
+
+// Contains reports whether word is a stop word.
+func (s StopWords) Contains(word string) bool {
+	_, found := s[word]
+	return found
+}
+
+// BuiltinStopWords returns the embedded stop word list for a language.
+// The lists are lowercase, so they are meant to be used together with a lowercasing normalization.
+func BuiltinStopWords(lang Language) (StopWords, error) {
+	f, err := stopWordsFS.Open("stopwords/" + string(lang) + ".txt")
+	if err != nil {
+		return nil, fmt.Errorf("no built-in stop words for language %q", lang)
+	}
+	defer f.Close()
+	return ReadStopWords(f)
+}
+
+// ReadStopWords reads a stop word list from r, one word per line.
+// Surrounding white space is trimmed; empty lines and lines starting with '#' are ignored.
+func ReadStopWords(r io.Reader) (StopWords, error) {
+	sw := make(StopWords)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		sw[word] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sw, nil
+}
+
+// LoadStopWords reads a stop word list from the file at path, in the format accepted by ReadStopWords.
+func LoadStopWords(path string) (StopWords, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadStopWords(f)
+}
+
+// WithStopWords is a functional option to drop stop words from the tokens.
+// It appends a StopWordsFilter to the filter pipeline, so stop words are removed after
+// normalization, in the order the options are given, and before n-grams are built.
+// It can be used multiple times, e.g. to combine several languages.
+//
+// Example:
+//
+//	english, _ := BuiltinStopWords(English)
+//	tokenizer := NewTokenizer(WithNormalizeFunc(strings.ToLower), WithStopWords(english))
+func WithStopWords(sw StopWords) TokenizerOption {
+	return WithFilters(StopWordsFilter(sw))
+}
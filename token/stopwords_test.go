@@ -0,0 +1,105 @@
+package token
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinStopWords(t *testing.T) {
+	tests := []struct {
+		lang     Language
+		expected []string
+	}{
+		{lang: English, expected: []string{"the", "is", "this"}},
+		{lang: Italian, expected: []string{"il", "della", "sono"}},
+		{lang: German, expected: []string{"der", "und", "nicht"}},
+		{lang: French, expected: []string{"le", "les", "est"}},
+		{lang: Spanish, expected: []string{"el", "los", "que"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.lang), func(t *testing.T) {
+			sw, err := BuiltinStopWords(tt.lang)
+			if err != nil {
+				t.Fatalf("BuiltinStopWords error: %v", err)
+			}
+			for _, word := range tt.expected {
+				if !sw.Contains(word) {
+					t.Errorf("%q is not a stop word", word)
+				}
+			}
+			for word := range sw {
+				if strings.HasPrefix(word, "#") {
+					t.Errorf("comment line %q loaded as stop word", word)
+				}
+			}
+		})
+	}
+
+	if _, err := BuiltinStopWords("klingon"); err == nil {
+		t.Errorf("BuiltinStopWords() expected error on unknown language")
+	}
+}
+
+func TestLoadStopWords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stopwords.txt")
+	if err := os.WriteFile(path, []byte("# custom list\nfoo\n\n  bar  \n"), 0o600); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+
+	sw, err := LoadStopWords(path)
+	if err != nil {
+		t.Fatalf("LoadStopWords error: %v", err)
+	}
+	if len(sw) != 2 || !sw.Contains("foo") || !sw.Contains("bar") {
+		t.Errorf("got %v, want foo and bar", sw)
+	}
+
+	if _, err := LoadStopWords(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Errorf("LoadStopWords() expected error on missing file")
+	}
+}
+
+func TestTokenizer_StopWords(t *testing.T) {
+	english, err := BuiltinStopWords(English)
+	if err != nil {
+		t.Fatalf("BuiltinStopWords error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     []TokenizerOption
+		expected []string
+	}{
+		{
+			name:     "Applied after normalization",
+			opts:     []TokenizerOption{WithStopWords(english), WithNormalizeFunc(strings.ToLower)},
+			expected: []string{"big", "brother", "watching"},
+		},
+		{
+			name:     "Combined lists",
+			opts:     []TokenizerOption{WithNormalizeFunc(strings.ToLower), WithStopWords(english), WithStopWords(NewStopWords("big"))},
+			expected: []string{"brother", "watching"},
+		},
+		{
+			name:     "Before n-grams",
+			opts:     []TokenizerOption{WithNormalizeFunc(strings.ToLower), WithStopWords(english), WithNGramRange(2, 2)},
+			expected: []string{"big brother", "brother watching"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, tokens, err := NewTokenizer(tt.opts...).Tokenize([]string{"The Big Brother is watching"})
+			if err != nil {
+				t.Fatalf("Tokenize error: %v", err)
+			}
+			if !slices.Equal(tokens[0], tt.expected) {
+				t.Errorf("got %q, want %q", tokens[0], tt.expected)
+			}
+		})
+	}
+}
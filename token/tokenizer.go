@@ -1,30 +1,92 @@
 package token
 
 import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
 	"slices" // Importing the slices package for sorting.
-	"unicode"
+	"strings"
+
+	"github.com/rioloc/tfidf-go/internal/parallel"
 )
 
-// Tokenizer is a simple tokenizer implementation based on regular expressions.
+// Tokenizer splits documents into word tokens, either by a predefined class of characters
+// or by a regular expression, and runs them through a pipeline of filters.
 type Tokenizer struct {
+	class         TokenClass          // Predefined class of characters making up a token.
+	pattern       *regexp.Regexp      // An optional pattern matching tokens, taking precedence over the class.
 	normalizeFunc func(string) string // An optional function to normalize tokens (e.g., convert to lowercase).
+	minLength     int                 // Minimum number of runes in a token.
+	maxLength     int                 // Maximum number of runes in a token, 0 for no limit.
+	lengthSet     bool                // Whether the length range was set, otherwise the default applies.
+	ngramMin      int                 // Minimum number of words in an emitted n-gram.
+	ngramMax      int                 // Maximum number of words in an emitted n-gram.
+	ngramSep      string              // Separator used to join the words of an n-gram.
+	filters       []Filter            // Ordered pipeline of filters applied after normalization.
+	workers       int                 // Number of goroutines tokenizing documents, 0 for GOMAXPROCS.
 }
 
 // TokenizerOption is a function type that allows for configuring the Tokenizer.
 type TokenizerOption func(*Tokenizer)
 
 // WithNormalizeFunc is a functional option to set a normalization function for the Tokenizer.
-// This function will be applied to each token after extraction.
+// This function will be applied exactly once to each token after extraction, before any filter.
+// Use MapFilter to place a normalization at a given point of the filter pipeline instead.
 func WithNormalizeFunc(fn func(string) string) TokenizerOption {
 	return func(t *Tokenizer) {
 		t.normalizeFunc = fn
 	}
 }
 
+// WithTokenLengthRange is a functional option to keep only the extracted tokens with a number of runes
+// between min and max included, with a max of 0 meaning no upper bound. Lengths are checked on the tokens
+// as extracted, before normalization and filters. For example, (1, 0) keeps one-letter words, while
+// (2, 30) also drops absurdly long tokens like base64 blobs or URLs. Defaults to (2, 0) with a token class,
+// and to (0, 0) with a token pattern, whose matches are all kept.
+func WithTokenLengthRange(min, max int) TokenizerOption {
+	return func(t *Tokenizer) {
+		t.minLength = min
+		t.maxLength = max
+		t.lengthSet = true
+	}
+}
+
+// WithNGramRange is a functional option to emit contiguous word n-grams, with n between min and max
+// included, instead of single words. For example, (1, 2) emits unigrams and bigrams,
+// while (2, 2) only emits bigrams. Defaults to (1, 1).
+func WithNGramRange(min, max int) TokenizerOption {
+	return func(t *Tokenizer) {
+		t.ngramMin = min
+		t.ngramMax = max
+	}
+}
+
+// WithNGramSeparator is a functional option to set the string used to join the words of an n-gram.
+// Defaults to a single space.
+func WithNGramSeparator(sep string) TokenizerOption {
+	return func(t *Tokenizer) {
+		t.ngramSep = sep
+	}
+}
+
+// WithWorkers is a functional option to set the number of goroutines Tokenize shards documents across.
+// The output is identical whatever the number of workers. Defaults to GOMAXPROCS; with more than one worker,
+// the normalization function and the filters must be safe for concurrent use, which built-in ones are.
+func WithWorkers(n int) TokenizerOption {
+	return func(t *Tokenizer) {
+		t.workers = n
+	}
+}
+
 // NewTokenizer is a constructor function that creates and returns a new Tokenizer instance.
 // It accepts a variable number of TokenizerOption functions to configure the tokenizer.
 func NewTokenizer(opts ...TokenizerOption) *Tokenizer {
-	t := &Tokenizer{}
+	t := &Tokenizer{
+		ngramMin: 1,
+		ngramMax: 1,
+		ngramSep: " ",
+	}
 
 	// Apply all provided options to the tokenizer.
 	for _, opt := range opts {
@@ -36,24 +98,135 @@ func NewTokenizer(opts ...TokenizerOption) *Tokenizer {
 
 // Tokenize takes a slice of documents and returns a vocabulary (unique tokens)
 // and a 2D slice representing the tokens for each document.
+// Each extracted word goes through the normalization function, then through the filters
+// in order. If an n-gram range is set, the tokens are the n-grams built from the filtered words.
 func (t *Tokenizer) Tokenize(documents []string) ([]string, [][]string, error) {
+	return t.TokenizeContext(context.Background(), documents)
+}
+
+// TokenizeContext is like Tokenize, but checks ctx between documents and returns ctx.Err()
+// as soon as ctx is done.
+func (t *Tokenizer) TokenizeContext(ctx context.Context, documents []string) ([]string, [][]string, error) {
+	if err := t.validate(); err != nil {
+		return nil, nil, err
+	}
+
 	tokens := make([][]string, len(documents))
-	// Process each document individually.
-	for i, doc := range documents {
-		tkns := t.tokenize(doc)
-		if t.normalizeFunc != nil {
-			for j, term := range tkns {
-				tkns[j] = t.normalizeFunc(term)
+	// Process each document individually, sharding documents across workers.
+	err := parallel.For(t.workers, len(documents), func(_, lo, hi int) error {
+		for i := lo; i < hi; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
 			}
+			tokens[i] = t.tokenizeDocument(documents[i])
 		}
-		tokens[i] = tkns
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 	return vocabulary(tokens), tokens, nil
 }
 
-// tokenize extracts tokens from a single document string based on the tokenizer's pattern.
-// If a normalize function is set, it applies normalization to each extracted term.
+// TokenizeReader reads a single document from r and returns its tokens, like Tokenize does.
+// It allows feeding documents one at a time, e.g. from files, to a streaming fit.
+func (t *Tokenizer) TokenizeReader(r io.Reader) ([]string, error) {
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	doc, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return t.tokenizeDocument(string(doc)), nil
+}
+
+// validate checks the n-gram and token length ranges.
+func (t *Tokenizer) validate() error {
+	if ngramMin, ngramMax := t.ngramRange(); ngramMin < 1 || ngramMax < ngramMin {
+		return errors.New("invalid n-gram range")
+	}
+	if minLength, maxLength := t.lengthRange(); minLength < 0 || maxLength < 0 || (maxLength > 0 && maxLength < minLength) {
+		return errors.New("invalid token length range")
+	}
+	return nil
+}
+
+// tokenizeDocument runs a single document through the whole pipeline:
+// extraction, normalization, filters and n-grams.
+func (t *Tokenizer) tokenizeDocument(doc string) []string {
+	tkns := t.tokenize(doc)
+	if t.normalizeFunc != nil {
+		for j, term := range tkns {
+			tkns[j] = t.normalizeFunc(term)
+		}
+	}
+	for _, f := range t.filters {
+		tkns = f.Filter(tkns)
+	}
+	return t.ngrams(tkns)
+}
+
+// ngrams builds the contiguous word n-grams of a document for every n in the tokenizer range,
+// grouped by increasing n. With the default (1, 1) range the words are returned unchanged.
+// Example, with range (1, 2):
+// Input: ["big", "brother", "is"]
+// Returns: ["big", "brother", "is", "big brother", "brother is"]
+func (t *Tokenizer) ngrams(words []string) []string {
+	ngramMin, ngramMax := t.ngramRange()
+	if ngramMin == 1 && ngramMax == 1 {
+		return words
+	}
+
+	var grams []string
+	for n := ngramMin; n <= ngramMax; n++ {
+		for i := 0; i+n <= len(words); i++ {
+			if n == 1 {
+				grams = append(grams, words[i])
+				continue
+			}
+			grams = append(grams, strings.Join(words[i:i+n], t.ngramSep))
+		}
+	}
+	return grams
+}
+
+// ngramRange returns the n-gram range of the tokenizer, where an unset (0, 0) range,
+// as in a zero-value Tokenizer, means single words.
+func (t *Tokenizer) ngramRange() (min, max int) {
+	if t.ngramMin == 0 && t.ngramMax == 0 {
+		return 1, 1
+	}
+	return t.ngramMin, t.ngramMax
+}
+
+// lengthRange returns the token length range of the tokenizer. Unless set with WithTokenLengthRange,
+// as in a zero-value Tokenizer, tokens of the class need at least 2 runes, while pattern matches are all kept.
+func (t *Tokenizer) lengthRange() (min, max int) {
+	if !t.lengthSet {
+		if t.pattern != nil {
+			return 0, 0
+		}
+		return 2, 0
+	}
+	return t.minLength, t.maxLength
+}
+
+// tokenize extracts tokens from a single document string based on the tokenizer's pattern,
+// or on its token class if no pattern is set, and drops the tokens out of the length range.
 func (t *Tokenizer) tokenize(doc string) []string {
+	minLength, maxLength := t.lengthRange()
+	if t.pattern != nil {
+		tokens := t.pattern.FindAllString(doc, -1)
+		kept := tokens[:0]
+		for _, token := range tokens {
+			if inLength(token, minLength, maxLength) {
+				kept = append(kept, token)
+			}
+		}
+		return kept
+	}
+	inToken, joiner := t.class.runes()
 
 	// estimate the number of tokens to avoid reallocations
 	// this is a rough estimate, but it's good enough for most cases
@@ -64,33 +237,28 @@ func (t *Tokenizer) tokenize(doc string) []string {
 	// zero allocation tokenization
 	start := -1
 	for i, r := range doc {
-		if unicode.IsLetter(r) {
+		if inToken(r) {
 			if start == -1 {
 				start = i
 			}
 			continue
 		}
-		if start != -1 && i-start > 1 {
-			tokens = append(tokens, t.doNormalize(doc[start:i]))
+		if start != -1 && joiner != nil && joiner(r) && joined(doc, i, r, inToken) {
+			continue
+		}
+		if start != -1 && inLength(doc[start:i], minLength, maxLength) {
+			tokens = append(tokens, doc[start:i])
 		}
 		start = -1
 	}
 	// handle trailing token
-	if start != -1 && len(doc)-start > 1 {
-		tokens = append(tokens, t.doNormalize(doc[start:]))
+	if start != -1 && inLength(doc[start:], minLength, maxLength) {
+		tokens = append(tokens, doc[start:])
 	}
 
 	return tokens
 }
 
-// doNormalize applies the normalization function to a token if it is defined
-func (t *Tokenizer) doNormalize(token string) string {
-	if t.normalizeFunc != nil {
-		return t.normalizeFunc(token)
-	}
-	return token
-}
-
 // vocabulary extracts all unique tokens from a 2D slice of tokens (documents)
 // and returns them as a sorted slice of strings.
 // Example:
@@ -1,6 +1,14 @@
 package token
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
 
 func TestTokenizer_Tokenize(t *testing.T) {
 	tests := []struct {
@@ -40,3 +48,271 @@ func TestTokenizer_Tokenize(t *testing.T) {
 		})
 	}
 }
+
+func TestTokenizer_NGrams(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []TokenizerOption
+		doc      string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "Unigrams by default",
+			doc:      "Big Brother is watching",
+			expected: []string{"Big", "Brother", "is", "watching"},
+		},
+		{
+			name:     "Unigrams and bigrams",
+			opts:     []TokenizerOption{WithNGramRange(1, 2)},
+			doc:      "Big Brother is watching",
+			expected: []string{"Big", "Brother", "is", "watching", "Big Brother", "Brother is", "is watching"},
+		},
+		{
+			name:     "Trigrams only with separator",
+			opts:     []TokenizerOption{WithNGramRange(3, 3), WithNGramSeparator("_")},
+			doc:      "Big Brother is watching",
+			expected: []string{"Big_Brother_is", "Brother_is_watching"},
+		},
+		{
+			name:     "Document shorter than n",
+			opts:     []TokenizerOption{WithNGramRange(3, 3)},
+			doc:      "Big Brother",
+			expected: nil,
+		},
+		{
+			name:     "Normalization before joining",
+			opts:     []TokenizerOption{WithNGramRange(2, 2), WithNormalizeFunc(strings.ToLower)},
+			doc:      "BIG Brother",
+			expected: []string{"big brother"},
+		},
+		{
+			name:     "Unset range",
+			opts:     []TokenizerOption{WithNGramRange(0, 0)},
+			doc:      "Big Brother",
+			expected: []string{"Big", "Brother"},
+		},
+		{
+			name:    "Invalid range",
+			opts:    []TokenizerOption{WithNGramRange(2, 1)},
+			doc:     "Big Brother",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, tokens, err := NewTokenizer(tt.opts...).Tokenize([]string{tt.doc})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Tokenize() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Tokenize error: %v", err)
+			}
+			if !slices.Equal(tokens[0], tt.expected) {
+				t.Errorf("got %q, want %q", tokens[0], tt.expected)
+			}
+		})
+	}
+}
+
+func TestTokenizer_ZeroValue(t *testing.T) {
+	var tokenizer Tokenizer
+	_, tokens, err := tokenizer.Tokenize([]string{"a é Big Brother is watching"})
+	if err != nil {
+		t.Fatalf("Tokenize error: %v", err)
+	}
+	expected := []string{"Big", "Brother", "is", "watching"}
+	if !slices.Equal(tokens[0], expected) {
+		t.Errorf("got %q, want %q", tokens[0], expected)
+	}
+}
+
+func TestTokenizer_TokenLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []TokenizerOption
+		doc      string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "Two runes by default",
+			doc:      "a é 日本 language",
+			expected: []string{"日本", "language"},
+		},
+		{
+			name:     "One letter tokens",
+			opts:     []TokenizerOption{WithTokenLengthRange(1, 0)},
+			doc:      "a é 日本 language",
+			expected: []string{"a", "é", "日本", "language"},
+		},
+		{
+			name:     "Maximum length",
+			opts:     []TokenizerOption{WithTokenLengthRange(2, 4)},
+			doc:      "città aGVsbGxvIHdvcmxk and più",
+			expected: []string{"and", "più"},
+		},
+		{
+			name:     "Pattern matches kept by default",
+			opts:     []TokenizerOption{WithTokenPattern(regexp.MustCompile(`\w+`))},
+			doc:      "I am a x y go",
+			expected: []string{"I", "am", "a", "x", "y", "go"},
+		},
+		{
+			name:     "Applied to pattern matches",
+			opts:     []TokenizerOption{WithTokenPattern(regexp.MustCompile(`\S+`)), WithTokenLengthRange(1, 3)},
+			doc:      "I like C++ programming",
+			expected: []string{"I", "C++"},
+		},
+		{
+			name:    "Invalid range",
+			opts:    []TokenizerOption{WithTokenLengthRange(3, 2)},
+			doc:     "language",
+			wantErr: true,
+		},
+		{
+			name:    "Negative minimum",
+			opts:    []TokenizerOption{WithTokenLengthRange(-1, 0)},
+			doc:     "language",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, tokens, err := NewTokenizer(tt.opts...).Tokenize([]string{tt.doc})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Tokenize() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Tokenize error: %v", err)
+			}
+			if !slices.Equal(tokens[0], tt.expected) {
+				t.Errorf("got %q, want %q", tokens[0], tt.expected)
+			}
+		})
+	}
+}
+
+func TestTokenizeReader(t *testing.T) {
+	docs := []string{"Big Brother is watching you", "To be or not to be", ""}
+
+	tests := []struct {
+		name      string
+		tokenizer interface {
+			Tokenize(documents []string) ([]string, [][]string, error)
+			TokenizeReader(r io.Reader) ([]string, error)
+		}
+	}{
+		{name: "words", tokenizer: NewTokenizer(WithNormalizeFunc(strings.ToLower), WithNGramRange(1, 2))},
+		{name: "char n-grams", tokenizer: NewCharNGramTokenizer(WithCharMode(CharWB), WithCharNGramRange(2, 3))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, want, err := tt.tokenizer.Tokenize(docs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, doc := range docs {
+				got, err := tt.tokenizer.TokenizeReader(strings.NewReader(doc))
+				if err != nil {
+					t.Fatalf("TokenizeReader() error = %v", err)
+				}
+				if !slices.Equal(got, want[i]) {
+					t.Errorf("TokenizeReader(%q) = %v, want %v", doc, got, want[i])
+				}
+			}
+		})
+	}
+
+	if _, err := NewTokenizer(WithNGramRange(2, 1)).TokenizeReader(strings.NewReader("a b")); err == nil {
+		t.Error("TokenizeReader() with invalid n-gram range: expected error")
+	}
+}
+
+func TestTokenizeContext(t *testing.T) {
+	documents := []string{"Big Brother is watching you", "To be or not to be"}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name      string
+		tokenizer interface {
+			Tokenize(documents []string) ([]string, [][]string, error)
+			TokenizeContext(ctx context.Context, documents []string) ([]string, [][]string, error)
+		}
+	}{
+		{name: "words", tokenizer: NewTokenizer(WithNormalizeFunc(strings.ToLower))},
+		{name: "char n-grams", tokenizer: NewCharNGramTokenizer(WithCharMode(CharWB))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantVocab, want, _ := tt.tokenizer.Tokenize(documents)
+			vocab, got, err := tt.tokenizer.TokenizeContext(context.Background(), documents)
+			if err != nil {
+				t.Fatalf("TokenizeContext() error = %v", err)
+			}
+			if !slices.Equal(vocab, wantVocab) || !slices.EqualFunc(got, want, slices.Equal[[]string]) {
+				t.Errorf("TokenizeContext() = %q, want %q", got, want)
+			}
+			if _, _, err := tt.tokenizer.TokenizeContext(canceled, documents); !errors.Is(err, context.Canceled) {
+				t.Errorf("TokenizeContext() error = %v, want %v", err, context.Canceled)
+			}
+		})
+	}
+}
+
+func TestTokenizer_Workers(t *testing.T) {
+	docs := make([]string, 50)
+	for i := range docs {
+		docs[i] = strings.Repeat("Big Brother is watching you ", i%5) + strings.Repeat("to be or not ", i%3)
+	}
+
+	tests := []struct {
+		name       string
+		sequential interface {
+			Tokenize(documents []string) ([]string, [][]string, error)
+		}
+		parallel interface {
+			Tokenize(documents []string) ([]string, [][]string, error)
+		}
+	}{
+		{
+			name:       "words",
+			sequential: NewTokenizer(WithWorkers(1), WithNGramRange(1, 2), WithStemmer(StemEnglish)),
+			parallel:   NewTokenizer(WithWorkers(4), WithNGramRange(1, 2), WithStemmer(StemEnglish)),
+		},
+		{
+			name:       "char n-grams",
+			sequential: NewCharNGramTokenizer(WithCharWorkers(1), WithCharMode(CharWB)),
+			parallel:   NewCharNGramTokenizer(WithCharWorkers(4), WithCharMode(CharWB)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantVocab, want, _ := tt.sequential.Tokenize(docs)
+			gotVocab, got, err := tt.parallel.Tokenize(docs)
+			if err != nil {
+				t.Fatalf("Tokenize() error = %v", err)
+			}
+			if !slices.Equal(gotVocab, wantVocab) {
+				t.Errorf("vocabulary = %v, want %v", gotVocab, wantVocab)
+			}
+			for i := range want {
+				if !slices.Equal(got[i], want[i]) {
+					t.Errorf("tokens[%d] = %v, want %v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
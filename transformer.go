@@ -0,0 +1,146 @@
+package tfidf
+
+import (
+	"context"
+	"errors"
+)
+
+// IdfTransformer learns the IDF vector from a term count matrix whose columns are already known,
+// like scikit-learn's TfidfTransformer, and weights count matrices with it.
+// It completes a HashingVectorizer into a TF-IDF pipeline without any stored vocabulary,
+// and works with any other source of term counts, like TfSparse.
+//
+// Example:
+//
+//	hv := NewHashingVectorizer(WithAlternateSign(false))
+//	counts, _ := hv.Transform(tokens)
+//	transformer := NewIdfTransformer(NewTfIdfVectorizer(WithTfScheme(SublinearTf)), SmoothIdf)
+//	docsMat, _ := transformer.FitTransform(counts)
+//	queryCounts, _ := hv.Transform(queryTokens)
+//	queryMat, _ := transformer.Transform(queryCounts)
+type IdfTransformer struct {
+	vectorizer *TfIdfVectorizer // Weighting and normalization applied on Transform
+	idfScheme  IdfScheme        // Formula used to compute the IDF
+
+	df     []int            // Document frequency of each column
+	idf    []float64        // IDF score of each column
+	nDocs  int              // Number of documents seen during Fit
+	fitted *TfIdfVectorizer // vectorizer with the corpus pivot learned during Fit, if any
+}
+
+// NewIdfTransformer creates a new, unfitted IDF transformer weighting counts with the vectorizer,
+// or with NewTfIdfVectorizer() if nil, and computing the IDF vector with the given scheme.
+func NewIdfTransformer(vectorizer *TfIdfVectorizer, scheme IdfScheme) *IdfTransformer {
+	if vectorizer == nil {
+		vectorizer = NewTfIdfVectorizer()
+	}
+	return &IdfTransformer{
+		vectorizer: vectorizer,
+		idfScheme:  scheme,
+	}
+}
+
+// Fit learns the document frequency and the IDF of every column of a term count matrix.
+// A document contains a column if its count is not zero. Any previously fitted state is replaced.
+//
+// Parameters:
+//   - counts: Sparse term count matrix [documents][columns], e.g. from HashingVectorizer.Transform
+//
+// Returns:
+//   - err: Error if the matrix has no rows, if the IDF scheme is invalid, or if the vectorizer
+//     cannot weight its negative counts, see Transform
+func (x *IdfTransformer) Fit(counts *SparseMatrix) error {
+	if counts.Rows() == 0 {
+		return errors.New("empty TF matrix")
+	}
+	if err := x.checkSigns(counts); err != nil {
+		return err
+	}
+
+	df := make([]int, counts.Cols)
+	for k, j := range counts.Indices {
+		if counts.Values[k] != 0 {
+			df[j]++
+		}
+	}
+	idf, err := IdfFromDf(df, counts.Rows(), x.idfScheme)
+	if err != nil {
+		return err
+	}
+	fitted, err := x.vectorizer.withFittedPivot(context.Background(), counts, idf)
+	if err != nil {
+		return err
+	}
+
+	x.df = df
+	x.idf = idf
+	x.nDocs = counts.Rows()
+	x.fitted = fitted
+	return nil
+}
+
+// Transform weights a term count matrix with the fitted IDF vector, and normalizes its rows,
+// according to the transformer's vectorizer.
+// Negative counts, like those of a HashingVectorizer with alternate signs, can only be weighted
+// with RawTf or BinaryTf and a non-pivoted normalization level.
+//
+// Returns:
+//   - tfIdfMat: Sparse TF-IDF matrix [documents][columns]; counts is not modified
+//   - err: ErrNotFitted if Fit has not been called, an error if counts has negative values the
+//     vectorizer cannot weight, or an error from the vectorizer, e.g. if the number of columns
+//     differs from the fitted matrix
+func (x *IdfTransformer) Transform(counts *SparseMatrix) (*SparseMatrix, error) {
+	if !x.Fitted() {
+		return nil, ErrNotFitted
+	}
+	if err := x.checkSigns(counts); err != nil {
+		return nil, err
+	}
+	return x.fitted.TfIdfSparse(counts, x.idf)
+}
+
+// FitTransform fits the transformer on a term count matrix and returns its TF-IDF matrix.
+// It is equivalent to calling Fit followed by Transform on the same counts.
+func (x *IdfTransformer) FitTransform(counts *SparseMatrix) (*SparseMatrix, error) {
+	if err := x.Fit(counts); err != nil {
+		return nil, err
+	}
+	return x.Transform(counts)
+}
+
+// checkSigns returns an error if counts has negative values and the vectorizer uses a TF scheme
+// or a normalization level that assumes non-negative counts, e.g. taking their logarithm.
+func (x *IdfTransformer) checkSigns(counts *SparseMatrix) error {
+	v := x.vectorizer
+	if (v.TfScheme == RawTf || v.TfScheme == BinaryTf) && !v.pivoted() {
+		return nil
+	}
+	for _, val := range counts.Values {
+		if val < 0 {
+			return errors.New("negative counts require RawTf or BinaryTf and no pivoted normalization: disable alternate signs")
+		}
+	}
+	return nil
+}
+
+// Fitted reports whether the transformer has been fitted.
+func (x *IdfTransformer) Fitted() bool {
+	return x.idf != nil
+}
+
+// DocumentFrequencies returns, for each column, the number of fitted documents containing it.
+// The returned slice must not be modified.
+func (x *IdfTransformer) DocumentFrequencies() []int {
+	return x.df
+}
+
+// Idf returns the IDF vector learned during Fit, with one value per column.
+// The returned slice must not be modified.
+func (x *IdfTransformer) Idf() []float64 {
+	return x.idf
+}
+
+// NumDocuments returns the number of documents the transformer was fitted on.
+func (x *IdfTransformer) NumDocuments() int {
+	return x.nDocs
+}
@@ -0,0 +1,132 @@
+package tfidf
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestIdfTransformer_Hashing(t *testing.T) {
+	tokens := [][]string{
+		{"this", "is", "a", "sample", "document"},
+		{"this", "document", "is", "another", "example"},
+		{"and", "this", "is", "a", "different", "one"},
+		{"example", "example", "one"},
+	}
+	queries := [][]string{{"sample", "example", "example"}}
+
+	tests := []struct {
+		name       string
+		vectorizer *TfIdfVectorizer
+		scheme     IdfScheme
+	}{
+		{name: "defaults", vectorizer: nil, scheme: SmoothIdf},
+		{name: "sublinear l1", vectorizer: NewTfIdfVectorizer(WithTfScheme(SublinearTf), WithNormLevel(L1Norm)), scheme: StandardIdf},
+		{name: "pivoted", vectorizer: NewTfIdfVectorizer(WithNormLevel(PivotedNorm)), scheme: PlainIdf},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Without collisions, hashed columns are a permutation of the vocabulary columns
+			opts := []ModelOption{WithIdfScheme(tt.scheme)}
+			if tt.vectorizer != nil {
+				opts = append(opts, WithVectorizer(tt.vectorizer))
+			}
+			model := NewModel(opts...)
+			wantDocs, err := model.FitTransform(tokens)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantQueries, err := model.Transform(queries)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hv := NewHashingVectorizer(WithAlternateSign(false))
+			columns := make(map[int]bool)
+			for _, term := range model.Vocabulary() {
+				column, _ := hv.Feature(term)
+				columns[column] = true
+			}
+			if len(columns) != len(model.Vocabulary()) {
+				t.Fatal("vocabulary terms collide")
+			}
+
+			transformer := NewIdfTransformer(tt.vectorizer, tt.scheme)
+			counts, _ := hv.Transform(tokens)
+			gotDocs, err := transformer.FitTransform(counts)
+			if err != nil {
+				t.Fatalf("FitTransform() error = %v", err)
+			}
+			queryCounts, _ := hv.Transform(queries)
+			gotQueries, err := transformer.Transform(queryCounts)
+			if err != nil {
+				t.Fatalf("Transform() error = %v", err)
+			}
+			if transformer.NumDocuments() != len(tokens) {
+				t.Errorf("NumDocuments() = %d, want %d", transformer.NumDocuments(), len(tokens))
+			}
+
+			for _, c := range []struct {
+				got  *SparseMatrix
+				want [][]float64
+			}{{gotDocs, wantDocs}, {gotQueries, wantQueries}} {
+				for i, row := range c.want {
+					got := c.got.Row(i).Dense(hv.NumFeatures())
+					for j, term := range model.Vocabulary() {
+						column, _ := hv.Feature(term)
+						if math.Abs(got[column]-row[j]) > 1e-12 {
+							t.Errorf("row %d, term %q = %v, want %v", i, term, got[column], row[j])
+						}
+						if df := transformer.DocumentFrequencies()[column]; df != model.DocumentFrequencies()[j] {
+							t.Errorf("df of %q = %d, want %d", term, df, model.DocumentFrequencies()[j])
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestIdfTransformer_Errors(t *testing.T) {
+	counts := SparseFromDense([][]float64{{1, 0}, {0, 2}})
+
+	if _, err := NewIdfTransformer(nil, SmoothIdf).Transform(counts); !errors.Is(err, ErrNotFitted) {
+		t.Errorf("Transform() before Fit error = %v, want %v", err, ErrNotFitted)
+	}
+	if err := NewIdfTransformer(nil, SmoothIdf).Fit(NewSparseMatrix(2)); err == nil {
+		t.Error("Fit() on empty matrix: expected error")
+	}
+	if err := NewIdfTransformer(nil, IdfScheme(-1)).Fit(counts); err == nil {
+		t.Error("Fit() with invalid IDF scheme: expected error")
+	}
+
+	// Alternate signs make "abc" count negatively, which the logarithm of SublinearTf cannot weight
+	signed, _ := NewHashingVectorizer().Transform([][]string{{"abc", "abc", "hello"}})
+	sublinear := NewIdfTransformer(NewTfIdfVectorizer(WithTfScheme(SublinearTf)), SmoothIdf)
+	if _, err := sublinear.FitTransform(signed); err == nil {
+		t.Error("FitTransform() with negative counts and SublinearTf: expected error")
+	}
+	unsigned, _ := NewHashingVectorizer(WithAlternateSign(false)).Transform([][]string{{"abc", "abc", "hello"}})
+	if err := sublinear.Fit(unsigned); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sublinear.Transform(signed); err == nil {
+		t.Error("Transform() with negative counts and SublinearTf: expected error")
+	}
+	pivoted := NewIdfTransformer(NewTfIdfVectorizer(WithNormLevel(PivotedNorm)), SmoothIdf)
+	if err := pivoted.Fit(signed); err == nil {
+		t.Error("Fit() with negative counts and PivotedNorm: expected error")
+	}
+	if _, err := NewIdfTransformer(nil, SmoothIdf).FitTransform(signed); err != nil {
+		t.Errorf("FitTransform() with negative counts and RawTf error = %v", err)
+	}
+
+	transformer := NewIdfTransformer(nil, SmoothIdf)
+	if err := transformer.Fit(counts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := transformer.Transform(SparseFromDense([][]float64{{1, 0, 1}})); err == nil {
+		t.Error("Transform() with a different number of columns: expected error")
+	}
+}